@@ -2,6 +2,7 @@ package algorithm
 
 import (
 	"crypto"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
@@ -15,10 +16,21 @@ const minRSAKeyComplexity = 1024 // minimum length in bits
 
 // An algo is a verification algorithm as defined in the RFC 6376.
 type algo struct {
+	keyType  string
+	hash     crypto.Hash
 	hasher   func() hash.Hash
 	checkSig func(pubkey []byte, data []byte, signature []byte) error
 }
 
+// KeyType returns the k= key type of the algorithm, "rsa" or "ed25519".
+func (a algo) KeyType() string {
+	return a.keyType
+}
+
+func (a algo) Hash() crypto.Hash {
+	return a.hash
+}
+
 func (a algo) Hasher() func() hash.Hash {
 	return a.hasher
 }
@@ -54,7 +66,20 @@ func checkRsaSha256(pubkey []byte, data []byte, signature []byte) error {
 	return checkRsa(pubkey, data, signature, crypto.SHA256)
 }
 
+// checkEd25519Sha256 verifies an Ed25519 signature of RFC 8463, data is the SHA-256 hash of the headers,
+// which is signed as the message of PureEdDSA.
+func checkEd25519Sha256(pubkey []byte, data []byte, signature []byte) error {
+	if len(pubkey) != ed25519.PublicKeySize {
+		return errors.New("not an Ed25519 public key")
+	}
+	if !ed25519.Verify(pubkey, data, signature) {
+		return errors.New("invalid Ed25519 signature")
+	}
+	return nil
+}
+
 var algos = map[string]*algo{
-	"rsa-sha1":   {hasher: sha1.New, checkSig: checkRsaSha1},
-	"rsa-sha256": {hasher: sha256.New, checkSig: checkRsaSha256},
+	"rsa-sha1":       {keyType: "rsa", hash: crypto.SHA1, hasher: sha1.New, checkSig: checkRsaSha1},
+	"rsa-sha256":     {keyType: "rsa", hash: crypto.SHA256, hasher: sha256.New, checkSig: checkRsaSha256},
+	"ed25519-sha256": {keyType: "ed25519", hash: crypto.SHA256, hasher: sha256.New, checkSig: checkEd25519Sha256},
 }
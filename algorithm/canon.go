@@ -2,15 +2,22 @@ package algorithm
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 )
 
 // A canon is pair of canonicalization algorithms as defined in the RFC 6376.
 type canon struct {
+	name   string
 	header func(string) string
 	body   func(string) string
 }
 
+// Name returns the header and body algorithms of the canonicalization, e.g. "relaxed/relaxed".
+func (c canon) Name() string {
+	return c.name
+}
+
 func (c canon) Body() func(string) string {
 	return c.body
 }
@@ -188,11 +195,20 @@ func relaxBody(body string) string {
 	return body
 }
 
+// CanonName returns the name of the canonicalization of the c= tag value, e.g. "relaxed/relaxed" for "relaxed".
+func CanonName(value string) (string, error) {
+	c, found := canons[value]
+	if !found {
+		return "", errors.New("unknown canon")
+	}
+	return c.name, nil
+}
+
 var canons = map[string]*canon{
-	"simple/simple":   {header: simpleHeader, body: simpleBody},
-	"simple/relaxed":  {header: simpleHeader, body: relaxBody},
-	"relaxed/simple":  {header: relaxHeader, body: simpleBody},
-	"relaxed/relaxed": {header: relaxHeader, body: relaxBody},
-	"simple":          {header: simpleHeader, body: simpleBody},
-	"relaxed":         {header: relaxHeader, body: relaxBody},
+	"simple/simple":   {name: "simple/simple", header: simpleHeader, body: simpleBody},
+	"simple/relaxed":  {name: "simple/relaxed", header: simpleHeader, body: relaxBody},
+	"relaxed/simple":  {name: "relaxed/simple", header: relaxHeader, body: simpleBody},
+	"relaxed/relaxed": {name: "relaxed/relaxed", header: relaxHeader, body: relaxBody},
+	"simple":          {name: "simple/simple", header: simpleHeader, body: simpleBody},
+	"relaxed":         {name: "relaxed/relaxed", header: relaxHeader, body: relaxBody},
 }
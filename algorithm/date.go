@@ -0,0 +1,56 @@
+package algorithm
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DatePattern matches the relaxed canonical Date header of RFC 5322 with a numeric zone and an optional comment,
+// e.g. "date:Thu, 30 Oct 2025 03:17:50 +0000 (GMT)\r\n". The groups are the day, the month, the year, the hour,
+// the minute, the optional second, the zone sign, the zone hour and the zone minute.
+const DatePattern = `^date:(?:(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun), ?)?(0?[1-9]|[12][0-9]|3[01]) (Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) ([0-9]{4}) ([01][0-9]|2[0-3]):([0-5][0-9])(?::([0-5][0-9]|60))? ([+-])([01][0-9]|2[0-3])([0-5][0-9])(?: ?\([^()\r\n]*\))?\r\n$`
+
+// MinDateYear and MaxDateYear bound the years of the Date header.
+const (
+	MinDateYear = 1970
+	MaxDateYear = MinDateYear + 255
+)
+
+var dateRegexp = regexp.MustCompile(DatePattern)
+
+var months = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+
+// ParseDate returns the Unix timestamp of the Date header, it is canonicalized with the relaxed algorithm first.
+// Days past the end of the month and the leap second roll over to the next day and minute.
+func ParseDate(header string) (int64, error) {
+	return parseDate(relaxHeader(header))
+}
+
+func parseDate(header string) (int64, error) {
+	fields := dateRegexp.FindStringSubmatch(header)
+	if fields == nil {
+		return 0, errors.New("invalid date header")
+	}
+	number := func(field string) int {
+		value, _ := strconv.Atoi(field)
+		return value
+	}
+	year := number(fields[3])
+	if year < MinDateYear || year > MaxDateYear {
+		return 0, errors.New("date header year out of range")
+	}
+	month := 0
+	for i, name := range months {
+		if name == fields[2] {
+			month = i + 1
+		}
+	}
+	offset := number(fields[8])*3600 + number(fields[9])*60
+	if fields[7] == "-" {
+		offset = -offset
+	}
+	zone := time.FixedZone("", offset)
+	return time.Date(year, time.Month(month), number(fields[1]), number(fields[4]), number(fields[5]), number(fields[6]), 0, zone).Unix(), nil
+}
@@ -0,0 +1,31 @@
+package algorithm
+
+import (
+	"testing"
+)
+
+func TestParseDate(t *testing.T) {
+	cases := []struct {
+		header    string
+		timestamp int64
+	}{
+		{"Date: Thu, 30 Oct 2025 03:17:50 +0000 (GMT)\r\n", 1761794270},
+		{"date:Fri, 7 Nov 2025 17:36:42 +0800\r\n", 1762508202},
+		{"Date: 7 Nov 2025\r\n 09:36 +0000\r\n", 1762508160},
+		{"Date: Fri, 31 Feb 2025 00:00:00 -0130\r\n", 1740965400},
+	}
+	for _, c := range cases {
+		timestamp, err := ParseDate(c.header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if timestamp != c.timestamp {
+			t.Fatalf("%q: got %d, want %d", c.header, timestamp, c.timestamp)
+		}
+	}
+	for _, header := range []string{"Date: Thu, 30 Oct 2025 03:17:50 GMT\r\n", "Date: Thu, 32 Oct 2025 03:17:50 +0000\r\n", "Date: Thu, 30 Oct 1969 03:17:50 +0000\r\n"} {
+		if _, err := ParseDate(header); err == nil {
+			t.Fatalf("%q: want error", header)
+		}
+	}
+}
@@ -64,6 +64,18 @@ func extractHeaders(headers []string, names []string) []string {
 	return extracted
 }
 
+// headersHash hashes the canonical signed headers followed by the signature header without its b= value.
+func (s *Signature) headersHash(signedHeaders []string) []byte {
+	h := s.algo.hasher()
+	for _, header := range signedHeaders {
+		header = s.canon.header(header)
+		h.Write([]byte(header))
+	}
+	header := s.canon.header(s.trimmedHeader)
+	h.Write([]byte(header))
+	return h.Sum(nil)
+}
+
 // ParseAndVerify parses the e-mail, searches for a DKIM signature, verifies
 // the signature, and returns the resulting verified e-mail. Results in an
 // error if there is no valid signature.
@@ -106,20 +118,14 @@ func ParseAndVerify(mail string, mode VerificationMode, dnsClient DNSClient) (*V
 	}
 
 	signedHeaders := extractHeaders(email.headers, signature.headerNames)
-
-	h := signature.algo.hasher()
-	for _, header := range signedHeaders {
-		header = signature.canon.header(header)
-		h.Write([]byte(header))
-	}
-	header := signature.canon.header(signature.trimmedHeader)
-	h.Write([]byte(header))
-
-	headersHash := h.Sum(nil)
+	headersHash := signature.headersHash(signedHeaders)
 
 	found := false
 	for _, txtRecord := range txtRecords {
 		pubkey := parsePubkey(txtRecord)
+		if pubkey.keyType != signature.algo.keyType {
+			continue
+		}
 		if err := signature.algo.checkSig(pubkey.key, headersHash, signature.signature); err == nil {
 			found = true
 		}
@@ -6,19 +6,25 @@ import (
 )
 
 type pubkey struct {
-	key []byte
+	keyType string
+	key     []byte
 }
 
 func (p pubkey) Key() []byte {
 	return p.key
 }
 
+// KeyType returns the k= key type of the record, "rsa" by default.
+func (p pubkey) KeyType() string {
+	return p.keyType
+}
+
 func trimWhitespace(in string) string {
 	return strings.Trim(in, "\r\n\t ")
 }
 
 func parsePubkey(txtRecord string) *pubkey {
-	pubkey := new(pubkey)
+	pubkey := &pubkey{keyType: "rsa"}
 
 	for _, pair := range strings.Split(txtRecord, ";") {
 		idx := strings.IndexByte(pair, '=')
@@ -28,6 +34,8 @@ func parsePubkey(txtRecord string) *pubkey {
 		k, v := trimWhitespace(pair[:idx]), trimWhitespace(pair[idx+1:])
 
 		switch k {
+		case "k":
+			pubkey.keyType = v
 		case "p":
 			pubkey.key, _ = base64.StdEncoding.DecodeString(stripWhitespace(v))
 		}
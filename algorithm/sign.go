@@ -0,0 +1,51 @@
+package algorithm
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SignEmail signs the named headers and the body of the e-mail with relaxed/relaxed canonicalization, and
+// returns the e-mail with the DKIM-Signature header prepended. The signer must be an *rsa.PrivateKey for
+// rsa-sha256 or an ed25519.PrivateKey for ed25519-sha256 of RFC 8463.
+func SignEmail(mail string, domain string, selector string, headerNames []string, signer crypto.Signer) (string, error) {
+	var algoName string
+	var opts crypto.SignerOpts
+	switch signer.(type) {
+	case *rsa.PrivateKey:
+		algoName, opts = "rsa-sha256", crypto.SHA256
+	case ed25519.PrivateKey:
+		// The header hash is signed as the message of PureEdDSA.
+		algoName, opts = "ed25519-sha256", crypto.Hash(0)
+	default:
+		return "", errors.New("unsupported private key")
+	}
+	email := parseEmail(mail)
+
+	h := algos[algoName].hasher()
+	h.Write([]byte(canons["relaxed/relaxed"].body(email.body)))
+	header := fmt.Sprintf("DKIM-Signature: v=1; a=%s; c=relaxed/relaxed;\r\n\td=%s; s=%s;\r\n\th=%s;\r\n\tbh=%s;\r\n\tb=",
+		algoName, domain, selector, strings.Join(headerNames, ":"), base64.StdEncoding.EncodeToString(h.Sum(nil)))
+	signature, err := parseSignature(header)
+	if err != nil {
+		return "", err
+	}
+
+	headersHash := signature.headersHash(extractHeaders(email.headers, signature.headerNames))
+	sig, err := signer.Sign(rand.Reader, headersHash, opts)
+	if err != nil {
+		return "", err
+	}
+	return header + base64.StdEncoding.EncodeToString(sig) + "\r\n" + mail, nil
+}
+
+// Ed25519TxtRecord returns the DKIM TXT record of the Ed25519 public key.
+func Ed25519TxtRecord(publicKey ed25519.PublicKey) string {
+	return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(publicKey)
+}
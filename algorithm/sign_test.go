@@ -0,0 +1,47 @@
+package algorithm
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/doubiliu/zk-email/utils"
+)
+
+var unsigned = utils.FixupNewlines(`From: Alice <alice@example.com>
+To: Bob <bob@example.org>
+Subject: Approve 0x1
+Date: Mon, 19 Oct 2026 10:00:00 +0000
+
+Hello Bob.
+`)
+
+func TestSignEd25519(t *testing.T) {
+	privateKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+	mail, err := SignEmail(unsigned, "example.com", "ed", []string{"from", "to", "subject", "date"}, privateKey)
+	if err != nil {
+		t.Fatalf("expected success; got %s", err)
+	}
+	client := &fakeDnsClient{
+		results: map[string][]string{
+			"ed._domainkey.example.com.": []string{Ed25519TxtRecord(privateKey.Public().(ed25519.PublicKey))},
+		},
+	}
+	email, err := ParseAndVerify(mail, Complete, client)
+	if err != nil {
+		t.Fatalf("expected success; got %s", err)
+	}
+	if email.Signature.Algo().KeyType() != "ed25519" {
+		t.Errorf("expected ed25519 key type; got %s", email.Signature.Algo().KeyType())
+	}
+
+	if _, err := ParseAndVerify(mail+"foobar", Complete, client); err == nil || err.Error() != "body hash does not match" {
+		t.Errorf("expected failing body hash; got %v", err)
+	}
+
+	// An RSA record of the same selector is not taken for the ed25519 signature.
+	client.results["ed._domainkey.example.com."] = []string{"v=DKIM1; k=rsa; " + Ed25519TxtRecord(privateKey.Public().(ed25519.PublicKey))[len("v=DKIM1; k=ed25519; "):]}
+	if _, err := ParseAndVerify(mail, Complete, client); err == nil || err.Error() != "no valid DKIM signature" {
+		t.Errorf("expected no valid DKIM signature; got %v", err)
+	}
+}
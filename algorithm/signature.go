@@ -95,6 +95,15 @@ func IsFromHeader(header string) bool {
 	return isFromHeader(header)
 }
 
+func isHeader(header string, name string) bool {
+	return strings.HasPrefix(strings.ToLower(header), strings.ToLower(name)+":")
+}
+
+// IsHeader reports whether the header has the name, case-insensitively.
+func IsHeader(header string, name string) bool {
+	return isHeader(header, name)
+}
+
 func ParseSignature(header string) (*Signature, error) {
 	return parseSignature(header)
 }
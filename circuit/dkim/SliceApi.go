@@ -1,12 +1,11 @@
 package dkim
 
 import (
+	"crypto"
 	"fmt"
 	"slices"
 
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/hash/sha2"
-	"github.com/consensys/gnark/std/math/uints"
 	"github.com/consensys/gnark/std/selector"
 )
 
@@ -121,6 +120,20 @@ func (c *SliceApi) LeftShift(a []frontend.Variable, shift frontend.Variable) []f
 	return rightShift
 }
 
+// shortRightShift shifts a right like RightShift by a shift of at most maxShift,
+// which costs far fewer constraints for short shifts. A shift out of the range gives zeros.
+func (c *SliceApi) shortRightShift(a []frontend.Variable, shift frontend.Variable, maxShift int) []frontend.Variable {
+	api := c.api
+	result := c.Zeros(len(a))
+	for k := 0; k <= maxShift; k++ {
+		isSelect := api.IsZero(api.Sub(shift, k))
+		for i := k; i < len(a); i++ {
+			result[i] = api.Add(result[i], api.Mul(a[i-k], isSelect))
+		}
+	}
+	return result
+}
+
 // Concat.
 // in little-endian, [s, 0...0] concat [a, 0...0] -> [a, s, 0...0].
 // in big-endian, [0...0, s] concat [0...0, a] -> [0...0, s, a].
@@ -327,7 +340,13 @@ func (s *PaddingSlice) Len(api frontend.API) frontend.Variable {
 	return sCopy.Padding
 }
 
+// GetSliceHash computes the SHA-256 hash of the data in the slice, the padding excluded.
 func (s *PaddingSlice) GetSliceHash(api frontend.API) ([]frontend.Variable, error) {
+	return s.GetSliceDigest(api, crypto.SHA256)
+}
+
+// GetSliceDigest computes the hash of the data in the slice with the hash function h, the padding excluded.
+func (s *PaddingSlice) GetSliceDigest(api frontend.API, h crypto.Hash) ([]frontend.Variable, error) {
 	resultSlice := s
 	generator := func(api frontend.API) []UndeterminedSlice {
 		slices := make([]UndeterminedSlice, 0)
@@ -349,28 +368,9 @@ func (s *PaddingSlice) GetSliceHash(api frontend.API) ([]frontend.Variable, erro
 	}
 	sliceComposer := NewSliceComposer(api)
 	fn := func(api frontend.API, slices ...UndeterminedSlice) (DeterminedSlice, error) {
-		data := slices[0].Variables
-		hasher, err := sha2.New(api)
-		if err != nil {
-			return nil, err
-		}
-		dataU8 := make([]uints.U8, len(data))
-		u8api, err := uints.New[uints.U32](api)
-		if err != nil {
-			return nil, err
-		}
-		for i := 0; i < len(data); i++ {
-			dataU8[i] = u8api.ByteValueOf(data[i])
-		}
-		hasher.Write(dataU8)
-		ru8 := hasher.Sum()
-		r := make([]frontend.Variable, len(ru8))
-		for i := 0; i < len(r); i++ {
-			r[i] = ru8[i].Val
-		}
-		return r, nil
+		return hashBytes(api, h, slices[0].Variables)
 	}
-	resultHash, err := sliceComposer.Process(32, fn, generator)
+	resultHash, err := sliceComposer.Process(h.Size(), fn, generator)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,94 @@
+package dkim
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// NewBase64Decode returns the base64 decoder of the standard alphabet with padding.
+func NewBase64Decode(api frontend.API) Base64Decode {
+	// Every byte maps to its index in the alphabet plus 1, or to 0 if it is not in the alphabet.
+	values := make([]int, 256)
+	for i := range base64Alphabet {
+		values[base64Alphabet[i]] = i + 1
+	}
+	t := logderivlookup.New(api)
+	for _, value := range values {
+		t.Insert(value)
+	}
+	return Base64Decode{api: api, t: t}
+}
+
+type Base64Decode struct {
+	api frontend.API
+	t   logderivlookup.Table
+}
+
+// Decode decodes the variable-length base64 data of the slice and returns the decoded bytes in a slice
+// of the same endianness. The data must be groups of 4 characters of the standard alphabet, only the last two
+// may be '=', and the bits left over by the padding must be 0 like in strict decoding, so every decoded
+// value has a single encoding. The result slice is base64.StdEncoding.DecodedLen(len(src.Slice)+3) bytes long.
+func (b64dec *Base64Decode) Decode(src PaddingSlice) PaddingSlice {
+	api := b64dec.api
+	sliceApi := NewSliceApi(api)
+	bigEndian := src.Clone()
+	if bigEndian.IsLittleEndian {
+		bigEndian = bigEndian.Reverse(api)
+	}
+	// The groups are aligned to the end of the slice, the data start must be aligned too.
+	aligned := logderivlookup.New(api)
+	for start := 0; start <= len(bigEndian.Slice); start++ {
+		if (len(bigEndian.Slice)-start)%4 == 0 {
+			aligned.Insert(1)
+		} else {
+			aligned.Insert(0)
+		}
+	}
+	start := api.Add(bigEndian.Padding, 1)
+	api.AssertIsEqual(aligned.Lookup(start)[0], 1)
+	front := (4 - len(bigEndian.Slice)%4) % 4
+	chars := append(sliceApi.Zeros(front), bigEndian.Slice...)
+	start = api.Add(start, front)
+	active := selector.StepMask(api, len(chars), start, 0, 1)
+	// Every character of the data is in the alphabet or is one of the last two padding characters,
+	// the padding is looked up as 0 like the bytes before the data.
+	last := len(chars) - 1
+	values := make([]frontend.Variable, len(chars))
+	pads := sliceApi.Zeros(len(chars))
+	for i := range chars {
+		char := api.Mul(chars[i], active[i])
+		value := b64dec.t.Lookup(char)[0]
+		inAlphabet := api.Sub(1, api.IsZero(value))
+		if i >= last-1 {
+			pads[i] = api.IsZero(api.Sub(char, '='))
+		}
+		api.AssertIsEqual(active[i], api.Add(inAlphabet, pads[i]))
+		values[i] = api.Sub(value, inAlphabet)
+	}
+	api.AssertIsEqual(api.Mul(pads[last-1], api.Sub(1, pads[last])), 0)
+	decoded := make([]frontend.Variable, 0, len(chars)/4*3)
+	length := frontend.Variable(0)
+	for i := 0; i < len(chars); i += 4 {
+		bits := make([]frontend.Variable, 0, 24)
+		for j := 3; j >= 0; j-- {
+			bits = append(bits, api.ToBinary(values[i+j], 6)...)
+		}
+		decoded = append(decoded, BitsToBytes(api, bits)...)
+		length = api.Add(length, api.Mul(active[i], 3))
+	}
+	// The bytes of the padding characters must be 0 and are dropped.
+	api.AssertIsEqual(api.Mul(pads[last], decoded[len(decoded)-1]), 0)
+	api.AssertIsEqual(api.Mul(pads[last-1], decoded[len(decoded)-2]), 0)
+	padding := api.Add(pads[last-1], pads[last])
+	decoded = sliceApi.shortRightShift(decoded, padding, 2)
+	result := PaddingSlice{
+		Padding:        api.Sub(len(decoded)-1, api.Sub(length, padding)),
+		Slice:          decoded,
+		IsLittleEndian: false,
+	}
+	if src.IsLittleEndian {
+		result = result.Reverse(api)
+	}
+	return result
+}
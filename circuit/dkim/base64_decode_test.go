@@ -0,0 +1,64 @@
+package dkim
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type Base64DecodeWrapper struct {
+	Data PaddingSlice
+	Aim  PaddingSlice
+}
+
+// Define declares the circuit's constraints.
+func (c *Base64DecodeWrapper) Define(api frontend.API) error {
+	decode := NewBase64Decode(api)
+	decodeData := decode.Decode(c.Data)
+	sliceApi := NewSliceApi(api)
+	sliceApi.AssertIsSame(decodeData.Slice, c.Aim.Slice)
+	api.AssertIsEqual(decodeData.Padding, c.Aim.Padding)
+	return nil
+}
+
+func TestBase64Decode(t *testing.T) {
+	assert := test.NewAssert(t)
+	length := 18
+	decodedLength := base64.StdEncoding.DecodedLen(length + 3)
+	for _, tc := range []struct {
+		data  string
+		valid bool
+	}{
+		{"", true},
+		{"UA==", true},
+		{"UEM=", true},
+		{"UENC", true},
+		{"emstZW1haWwhIQ==", true},
+		{"UA=", false},
+		{"UA", false},
+		{"UB==", false},
+		{"U===", false},
+		{"UA=A", false},
+		{"UE!C", false},
+		{"UA==UENC", false},
+	} {
+		decoded, _ := base64.StdEncoding.Strict().DecodeString(tc.data)
+		circuit := Base64DecodeWrapper{
+			Data: BytesToFixPadding(nil, false, length),
+			Aim:  BytesToFixPadding(nil, false, decodedLength),
+		}
+		assignment := Base64DecodeWrapper{
+			Data: BytesToFixPadding([]byte(tc.data), false, length),
+			Aim:  BytesToFixPadding(decoded, false, decodedLength),
+		}
+		err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		if tc.valid {
+			assert.NoError(err, tc.data)
+		} else {
+			assert.Error(err, tc.data)
+		}
+	}
+}
@@ -5,25 +5,16 @@ import (
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/selector"
 )
 
+// base64Alphabet is the alphabet of the standard base64 encoding.
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
 func NewBase64Encode(api frontend.API) Base64Encode {
 	t := logderivlookup.New(api)
-	base64Arr := []frontend.Variable{
-		frontend.Variable(byte('A')), frontend.Variable(byte('B')), frontend.Variable(byte('C')), frontend.Variable(byte('D')), frontend.Variable(byte('E')), frontend.Variable(byte('F')),
-		frontend.Variable(byte('G')), frontend.Variable(byte('H')), frontend.Variable(byte('I')), frontend.Variable(byte('J')), frontend.Variable(byte('K')), frontend.Variable(byte('L')),
-		frontend.Variable(byte('M')), frontend.Variable(byte('N')), frontend.Variable(byte('O')), frontend.Variable(byte('P')), frontend.Variable(byte('Q')), frontend.Variable(byte('R')),
-		frontend.Variable(byte('S')), frontend.Variable(byte('T')), frontend.Variable(byte('U')), frontend.Variable(byte('V')), frontend.Variable(byte('W')), frontend.Variable(byte('X')),
-		frontend.Variable(byte('Y')), frontend.Variable(byte('Z')), frontend.Variable(byte('a')), frontend.Variable(byte('b')), frontend.Variable(byte('c')), frontend.Variable(byte('d')),
-		frontend.Variable(byte('e')), frontend.Variable(byte('f')), frontend.Variable(byte('g')), frontend.Variable(byte('h')), frontend.Variable(byte('i')), frontend.Variable(byte('j')),
-		frontend.Variable(byte('k')), frontend.Variable(byte('l')), frontend.Variable(byte('m')), frontend.Variable(byte('n')), frontend.Variable(byte('o')), frontend.Variable(byte('p')),
-		frontend.Variable(byte('q')), frontend.Variable(byte('r')), frontend.Variable(byte('s')), frontend.Variable(byte('t')), frontend.Variable(byte('u')), frontend.Variable(byte('v')),
-		frontend.Variable(byte('w')), frontend.Variable(byte('x')), frontend.Variable(byte('y')), frontend.Variable(byte('z')), frontend.Variable(byte('0')), frontend.Variable(byte('1')),
-		frontend.Variable(byte('2')), frontend.Variable(byte('3')), frontend.Variable(byte('4')), frontend.Variable(byte('5')), frontend.Variable(byte('6')), frontend.Variable(byte('7')),
-		frontend.Variable(byte('8')), frontend.Variable(byte('9')), frontend.Variable(byte('+')), frontend.Variable(byte('/')),
-	}
-	for i := range base64Arr {
-		t.Insert(base64Arr[i])
+	for i := range base64Alphabet {
+		t.Insert(base64Alphabet[i])
 	}
 	return Base64Encode{api: api, t: t}
 }
@@ -33,6 +24,63 @@ type Base64Encode struct {
 	t   logderivlookup.Table
 }
 
+// Encode encodes fixed-length data, the rule is chosen by the remainder of the bit length divided by 6.
+func (b64enc *Base64Encode) Encode(srcData []frontend.Variable) []frontend.Variable {
+	switch (len(srcData) * 8) % 6 {
+	case 0:
+		return b64enc.EncodeRule1(srcData)
+	case 4:
+		return b64enc.EncodeRule2(srcData)
+	default:
+		return b64enc.EncodeRule3(srcData)
+	}
+}
+
+// EncodeSlice encodes the variable-length data of the slice with padding and returns the encoded data
+// in a slice of the same endianness, which is base64.StdEncoding.EncodedLen(len(src.Slice)+2) bytes long.
+func (b64enc *Base64Encode) EncodeSlice(src PaddingSlice) PaddingSlice {
+	api := b64enc.api
+	sliceApi := NewSliceApi(api)
+	bigEndian := src.Clone()
+	if bigEndian.IsLittleEndian {
+		bigEndian = bigEndian.Reverse(api)
+	}
+	// fill is the number of zero bytes completing the last group of 3 bytes, it is looked up by the data start.
+	fills := logderivlookup.New(api)
+	for start := 0; start <= len(bigEndian.Slice); start++ {
+		fills.Insert((3 - (len(bigEndian.Slice)-start)%3) % 3)
+	}
+	start := api.Add(bigEndian.Padding, 1)
+	fill := fills.Lookup(start)[0]
+	// [0...0, data] -> [0...0, data, 0^fill], so that the groups are aligned to the end.
+	data := sliceApi.shortRightShift(append(slices.Clone(bigEndian.Slice), 0, 0), api.Sub(2, fill), 2)
+	front := (3 - len(data)%3) % 3
+	data = append(sliceApi.Zeros(front), data...)
+	start = api.Sub(api.Add(start, front+2), fill)
+	active := selector.StepMask(api, len(data), start, 0, 1)
+	encoded := make([]frontend.Variable, 0, len(data)/3*4)
+	length := frontend.Variable(0)
+	for i := 0; i < len(data); i += 3 {
+		for _, b := range b64enc.encode(b64enc.split(data[i:i+3], 0)) {
+			encoded = append(encoded, api.Mul(b, active[i]))
+		}
+		length = api.Add(length, api.Mul(active[i], 4))
+	}
+	// The filled bytes of the last group are encoded as '='.
+	last := len(encoded) - 1
+	encoded[last] = api.Select(api.IsZero(fill), encoded[last], '=')
+	encoded[last-1] = api.Select(api.IsZero(api.Sub(fill, 2)), '=', encoded[last-1])
+	result := PaddingSlice{
+		Padding:        api.Sub(len(encoded)-1, length),
+		Slice:          encoded,
+		IsLittleEndian: false,
+	}
+	if src.IsLittleEndian {
+		result = result.Reverse(api)
+	}
+	return result
+}
+
 // Original data length is evenly divisible by 6 and the remainder is 0.
 func (b64enc *Base64Encode) EncodeRule1(srcData []frontend.Variable) []frontend.Variable {
 	remainder := b64enc.checkRemainder(srcData, 0)
@@ -1,6 +1,7 @@
 package dkim
 
 import (
+	"encoding/base64"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -85,3 +86,37 @@ func (c *Base64EncodeWrapper) Define(api frontend.API) error {
 	}
 	return nil
 }
+
+type Base64EncodeSliceWrapper struct {
+	Data PaddingSlice
+	Aim  PaddingSlice
+}
+
+// Define declares the circuit's constraints.
+func (c *Base64EncodeSliceWrapper) Define(api frontend.API) error {
+	encode := NewBase64Encode(api)
+	encodeData := encode.EncodeSlice(c.Data)
+	sliceApi := NewSliceApi(api)
+	sliceApi.AssertIsSame(encodeData.Slice, c.Aim.Slice)
+	api.AssertIsEqual(encodeData.Padding, c.Aim.Padding)
+	return nil
+}
+
+func TestBase64EncodeSlice(t *testing.T) {
+	assert := test.NewAssert(t)
+	length := 10
+	for _, data := range []string{"", "P", "PC", "PCB", "zk-email!!"} {
+		encoded := base64.StdEncoding.EncodeToString([]byte(data))
+		encodedLength := base64.StdEncoding.EncodedLen(length + 2)
+		circuit := Base64EncodeSliceWrapper{
+			Data: BytesToFixPadding(nil, false, length),
+			Aim:  BytesToFixPadding(nil, false, encodedLength),
+		}
+		assignment := Base64EncodeSliceWrapper{
+			Data: BytesToFixPadding([]byte(data), false, length),
+			Aim:  BytesToFixPadding([]byte(encoded), false, encodedLength),
+		}
+		err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err, data)
+	}
+}
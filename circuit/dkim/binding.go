@@ -0,0 +1,34 @@
+package dkim
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// AddressBits is the bit length of the caller address in the binding.
+const AddressBits = 160
+
+// BindingBits is the bit length of the binding, a 64 bits chain ID followed by the caller address.
+const BindingBits = AddressBits + 64
+
+// Binding returns the Binding public input of the circuit, chainID << 160 | caller,
+// which the contract compares with block.chainid and msg.sender.
+func Binding(caller [20]byte, chainID uint64) *big.Int {
+	binding := new(big.Int).SetUint64(chainID)
+	binding.Lsh(binding, AddressBits)
+	return binding.Or(binding, new(big.Int).SetBytes(caller[:]))
+}
+
+// ParseBinding returns the Binding public input of the hex caller address and the chain ID.
+func ParseBinding(caller string, chainID uint64) (*big.Int, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(caller, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 20 {
+		return nil, errors.New("caller address must be 20 bytes")
+	}
+	return Binding([20]byte(data), chainID), nil
+}
@@ -0,0 +1,45 @@
+package dkim
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestParseBinding(t *testing.T) {
+	assert := test.NewAssert(t)
+	binding, err := ParseBinding("0x00000000000000000000000000000000000000ff", 1)
+	assert.NoError(err)
+	expected := new(big.Int).Lsh(big.NewInt(1), AddressBits)
+	assert.Equal(expected.Add(expected, big.NewInt(0xff)), binding)
+	assert.True(binding.BitLen() <= BindingBits)
+	_, err = ParseBinding("0xff", 1)
+	assert.Error(err)
+}
+
+func TestBindingCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	maxBinding, err := ParseBinding("0xffffffffffffffffffffffffffffffffffffffff", math.MaxUint64)
+	assert.NoError(err)
+	for _, tc := range []struct {
+		binding *big.Int
+		valid   bool
+	}{
+		{big.NewInt(0), true},
+		{maxBinding, true},
+		{new(big.Int).Add(maxBinding, big.NewInt(1)), false},
+	} {
+		verifier, assignment := foxmailAssignment(assert)
+		assignment.SetBinding(tc.binding)
+		assert.Equal(tc.binding, assignment.Binding)
+		err := test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+		if tc.valid {
+			assert.NoError(err, tc.binding)
+		} else {
+			assert.Error(err, tc.binding)
+		}
+	}
+}
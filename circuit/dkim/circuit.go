@@ -1,240 +1,924 @@
 package dkim
 
 import (
+	"crypto"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"math/big"
+	"slices"
 	"strings"
 
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/hash/sha2"
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/math/emulated/emparams"
-	"github.com/consensys/gnark/std/math/uints"
 	"github.com/doubiliu/zk-email/algorithm"
 )
 
 type CustomDKIMVerifierWrapper[T emulated.FieldParams] struct {
+	// PubInputHash is the public input commitment, see CommitPublicInputs.
 	PubInputHash []frontend.Variable `gnark:",public"`
-	PublicKey    *PublicKey[T]
-	Header       CustomEmailHeader
-	Signature    EmailSig
+	// PubKeyHash is the hash of the DKIM public key, see PublicKeyHash. It is empty in the key set mode.
+	PubKeyHash []frontend.Variable `gnark:",public"`
+	// KeyRoot is the root of the key set tree in the key set mode, otherwise it is empty.
+	KeyRoot []frontend.Variable `gnark:",public"`
+	// KeyName is the commitment of the d= domain and the s= selector of the DKIM signature, see KeyNameCommitment,
+	// in the key name mode, otherwise it is empty.
+	KeyName []frontend.Variable `gnark:",public"`
+	// Command is the subject command argument packed into field elements, it is empty without the command mode.
+	Command []frontend.Variable `gnark:",public"`
+	// Sender is the commitment of the From address, see SenderCommitment, or of its domain in the domain only mode,
+	// see SenderDomainCommitment, salted in the salted sender mode. It is empty without the sender mode.
+	Sender []frontend.Variable `gnark:",public"`
+	// SenderMask marks the address in the revealed From header for the sender mode and the domain alignment.
+	SenderMask []frontend.Variable
+	// SenderSalt is the private salt of the sender commitment in the salted sender mode, otherwise it is empty.
+	SenderSalt []frontend.Variable
+	// SignatureDomainMask marks the d= domain in the trimmed DKIM-Signature header for the signature domain,
+	// the domain alignment and the key name.
+	SignatureDomainMask []frontend.Variable
+	// SelectorMask marks the s= selector in the trimmed DKIM-Signature header for the key name.
+	SelectorMask []frontend.Variable
+	// Timestamp is the t= signing time of the DKIM signature in the timestamp mode, see SignatureTimestamp,
+	// or the time of the Date header, see algorithm.ParseDate, otherwise it is empty.
+	Timestamp     []frontend.Variable `gnark:",public"`
+	TimestampMask []frontend.Variable
+	// Binding binds the proof to the caller address and the chain ID, see Binding, so that it can not be front run.
+	Binding frontend.Variable `gnark:",public"`
+	// Nullifier is derived from the DKIM signature, see Nullifier, so contracts can reject replays.
+	// It is the last public input.
+	Nullifier frontend.Variable `gnark:",public"`
+	KeyPath   KeyPath
+	// PublicKey is the RSA public key, N and E are 0 in the Ed25519 mode.
+	PublicKey *PublicKey[T]
+	// Ed25519Key holds the Ed25519 public key in the Ed25519 mode, otherwise it is empty.
+	Ed25519Key []Ed25519PublicKey
+	Header     CustomEmailHeader
+	Signature  EmailSig
+	Config     VerifierConfig `gnark:"-"`
 }
 
 // Define declares the circuit's constraints.
 func (c *CustomDKIMVerifierWrapper[T]) Define(api frontend.API) error {
-	// compute and check with public input hash
-	// pubkey N and E
+	// compute and check with public key hash
+	// pubkey N and E, E is the circuit constant in the fixed exponent mode, or the encoded Ed25519 key
 	f, err := emulated.NewField[T](api)
 	if err != nil {
 		return err
 	}
-	u8Api, err := uints.New[uints.U32](api)
+	var keyData []frontend.Variable
+	if c.Config.Ed25519 {
+		if len(c.Ed25519Key) != 1 {
+			return errors.New("Ed25519 mode requires the Ed25519 public key")
+		}
+		// N and E are not part of the Ed25519 key, they are kept at 0.
+		f.AssertIsEqual(&c.PublicKey.N, f.Zero())
+		f.AssertIsEqual(&c.PublicKey.E, f.Zero())
+		ed, err := NewEd25519(api)
+		if err != nil {
+			return err
+		}
+		keyData = ed.EncodePublicKey(&c.Ed25519Key[0])
+	} else {
+		keyData = BitsToBytes(api, f.ToBits(&c.PublicKey.N))
+		if c.Config.FixedExponent == 0 {
+			keyData = append(keyData, BitsToBytes(api, f.ToBits(&c.PublicKey.E))...)
+		} else {
+			e := new(big.Int).SetInt64(int64(c.Config.FixedExponent))
+			keyData = append(keyData, BytesToFrontVariable(e.FillBytes(make([]byte, keyBytes[T]())))...)
+		}
+	}
+	pubKeyHash, err := commitPublicInputs(api, c.Config.Commitment, keyData)
 	if err != nil {
 		return err
 	}
-	nBytes := BitsToBytes(api, f.ToBits(&c.PublicKey.N))
-	eBytes := BitsToBytes(api, f.ToBits(&c.PublicKey.E))
-	fromHash, err := c.Header.SpecifyData.GetSliceHash(api)
+	if c.Config.KeySetDepth != 0 {
+		if len(c.KeyRoot) != 1 || len(pubKeyHash) != 1 {
+			return errors.New("key set requires an algebraic commitment hash")
+		}
+		err = verifyKeyPath(api, c.Config.Commitment, pubKeyHash[0], c.KeyPath, c.KeyRoot[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		if len(c.PubKeyHash) != len(pubKeyHash) {
+			return errors.New("public key hash size mismatch")
+		}
+		for i := range c.PubKeyHash {
+			api.AssertIsEqual(c.PubKeyHash[i], pubKeyHash[i])
+		}
+	}
+	// the revealed headers are whole headers, which the sender, the command and the date parsing rely on
+	err = NewCustomEmailHeaderEncode(api).AssertHeaderBoundaries(c.Header, c.Config.RevealedHeaders)
 	if err != nil {
 		return err
 	}
-	pubInput := append(nBytes, eBytes...)
-	pubInput = append(pubInput, c.Signature.BodyHash[:]...)
-	pubInput = append(pubInput, fromHash...)
-	pubInputU8 := make([]uints.U8, len(pubInput))
-	for i, v := range pubInput {
-		pubInputU8[i] = u8Api.ByteValueOf(v)
+	// compute and check with public input hash
+	// body hash and the hash of every revealed header, except the headers left to the outputs, see hashedHeader
+	pubInput := make([]frontend.Variable, 0)
+	pubInput = append(pubInput, c.Signature.BodyHash...)
+	for i := range c.Header.SpecifyData {
+		if !c.Config.hashedHeader(i) {
+			continue
+		}
+		specifyHash, err := c.Header.SpecifyData[i].GetSliceHash(api)
+		if err != nil {
+			return err
+		}
+		pubInput = append(pubInput, specifyHash...)
 	}
-	hasher, err := sha2.New(api)
+	packedHash, err := commitPublicInputs(api, c.Config.Commitment, pubInput)
 	if err != nil {
 		return err
 	}
-	hasher.Write(pubInputU8)
-	pubInputHash := hasher.Sum()
+	if len(c.PubInputHash) != len(packedHash) {
+		return errors.New("public input hash size mismatch")
+	}
 	for i := range c.PubInputHash {
-		api.AssertIsEqual(c.PubInputHash[i], pubInputHash[i].Val)
+		api.AssertIsEqual(c.PubInputHash[i], packedHash[i])
+	}
+	// check the subject command
+	if c.Config.SubjectCommand != "" {
+		index := c.Config.revealedIndex("subject")
+		if index == -1 {
+			return errors.New("subject command requires the revealed subject header")
+		}
+		sliceApi := NewSliceApi(api)
+		arg, err := sliceApi.ExtractCommand(c.Header.SpecifyData[index], []byte(subjectHeaderName+c.Config.SubjectCommand), c.Config.CommandCapacity)
+		if err != nil {
+			return err
+		}
+		command := packBytes(api, arg)
+		if len(c.Command) != len(command) {
+			return errors.New("command size mismatch")
+		}
+		for i := range c.Command {
+			api.AssertIsEqual(c.Command[i], command[i])
+		}
+	}
+	trimmedHeader, err := NewEmailSigEncode(api).GetTrimmedHeader(c.Signature)
+	if err != nil {
+		return err
+	}
+	// the d= domain is shared by the signature domain, the key name and the domain alignment
+	var sigDomain []frontend.Variable
+	if c.Config.needsSignatureDomain() {
+		sliceApi := NewSliceApi(api)
+		sigDomain, err = sliceApi.signatureDomainBytes(trimmedHeader, c.SignatureDomainMask)
+		if err != nil {
+			return err
+		}
+	}
+	if c.Config.SignatureDomain != "" {
+		sliceApi := NewSliceApi(api)
+		sliceApi.assertSignatureDomain(sigDomain, c.Config.SignatureDomain)
+	}
+	// check the key name of the d= domain and the s= selector
+	if c.Config.KeyName {
+		sliceApi := NewSliceApi(api)
+		selector, err := sliceApi.ExtractSignatureSelector(trimmedHeader, c.SelectorMask, SelectorCapacity)
+		if err != nil {
+			return err
+		}
+		domain := sliceApi.packCaptured(sigDomain, sliceApi.nonZeroBits(sigDomain), DomainCapacity, false)
+		keyName, err := commitPublicInputs(api, c.Config.Commitment, append(domain, selector...))
+		if err != nil {
+			return err
+		}
+		if len(c.KeyName) != len(keyName) {
+			return errors.New("key name size mismatch")
+		}
+		for i := range c.KeyName {
+			api.AssertIsEqual(c.KeyName[i], keyName[i])
+		}
+	}
+	// check the sender address and the domain alignment, which share the address of the From header
+	if c.Config.SenderCapacity != 0 || c.Config.DomainAlignment != AlignmentNone {
+		fromIndex := c.Config.revealedIndex("from")
+		if fromIndex == -1 {
+			return errors.New("sender address and domain alignment require the revealed from header")
+		}
+		sliceApi := NewSliceApi(api)
+		address, domainBits, err := sliceApi.senderBytes(c.Header.SpecifyData[fromIndex], c.SenderMask)
+		if err != nil {
+			return err
+		}
+		domain := sliceApi.selectBytes(address, domainBits)
+		if c.Config.SenderCapacity != 0 {
+			var sender []frontend.Variable
+			if c.Config.SenderDomainOnly {
+				sender = sliceApi.packCaptured(domain, domainBits, c.Config.SenderCapacity, false)
+			} else {
+				sender = sliceApi.packCaptured(address, sliceApi.nonZeroBits(address), c.Config.SenderCapacity, false)
+			}
+			senderHash, err := commitPublicInputs(api, c.Config.Commitment, append(sender, c.SenderSalt...))
+			if err != nil {
+				return err
+			}
+			if len(c.Sender) != len(senderHash) {
+				return errors.New("sender size mismatch")
+			}
+			for i := range c.Sender {
+				api.AssertIsEqual(c.Sender[i], senderHash[i])
+			}
+		}
+		if c.Config.DomainAlignment != AlignmentNone {
+			sliceApi.assertDomainAlignment(domain, domainBits, sigDomain, c.Config.DomainAlignment)
+		}
+	}
+	// check the signing time, from the t= tag or from the Date header
+	if c.Config.Timestamp {
+		var timestamp frontend.Variable
+		sliceApi := NewSliceApi(api)
+		if c.Config.DateTimestamp {
+			index := c.Config.revealedIndex("date")
+			if index == -1 {
+				return errors.New("date timestamp requires the revealed date header")
+			}
+			timestamp, err = sliceApi.ParseDate(c.Header.SpecifyData[index])
+		} else {
+			timestamp, err = sliceApi.ExtractSignatureTimestamp(trimmedHeader, c.TimestampMask)
+		}
+		if err != nil {
+			return err
+		}
+		if len(c.Timestamp) != 1 {
+			return errors.New("timestamp size mismatch")
+		}
+		api.AssertIsEqual(c.Timestamp[0], timestamp)
 	}
-	return verifyCustomEmail(api, c.Header, c.Signature, *c.PublicKey)
+	// the binding is range checked, which also keeps the public input in the constraints
+	api.ToBinary(c.Binding, BindingBits)
+	// check the nullifier of the signature
+	sigNullifier, err := nullifier(api, c.Config.Commitment, c.Signature.SigContent)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(c.Nullifier, sigNullifier)
+	return verifyCustomEmail(api, c.Header, trimmedHeader, c.Signature, *c.PublicKey, c.Ed25519Key, c.Config)
 }
 
-// verifyCustomEmail verifies the DKIM signature within the circuit.
-func verifyCustomEmail[T emulated.FieldParams](api frontend.API, header CustomEmailHeader, sig EmailSig, publicKey PublicKey[T]) error {
+// verifyCustomEmail verifies the DKIM signature within the circuit, trimmedHeader is built from sig by GetTrimmedHeader.
+// ed25519Key holds the public key in the Ed25519 mode.
+func verifyCustomEmail[T emulated.FieldParams](api frontend.API, header CustomEmailHeader, trimmedHeader PaddingSlice, sig EmailSig, publicKey PublicKey[T], ed25519Key []Ed25519PublicKey, cfg VerifierConfig) error {
 	headerEncode := NewCustomEmailHeaderEncode(api)
 	//bodyEncode := NewEmailBodyEncode(api)
-	sigEncode := NewEmailSigEncode(api)
-	trimmedHeader, err := sigEncode.GetTrimmedHeader(sig)
+	headerHash, err := headerEncode.GetHeaderDigest(header, trimmedHeader, cfg.Hash)
 	if err != nil {
 		return err
 	}
-	headerHash, err := headerEncode.GetHeaderHash(header, trimmedHeader)
-	if err != nil {
-		return err
+	if cfg.Ed25519 {
+		// The header hash is signed as the message, see RFC 8463.
+		ed, err := NewEd25519(api)
+		if err != nil {
+			return err
+		}
+		return ed.Verify(&ed25519Key[0], sig.SigContent, headerHash)
 	}
 	rsa := NewRSA[T](api)
-	err = rsa.VerifyPkcs1v15(&publicKey, sig.SigContent, headerHash)
+	if cfg.FixedExponent != 0 {
+		rsa = NewFixedExponentRSA[T](api, cfg.FixedExponent)
+	}
+	err = rsa.VerifyPkcs1v15(&publicKey, cfg.Hash, sig.SigContent, headerHash)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// GetCustomDKIMVerifierWrapper returns a DKIM verifier circuit template for the specified mail type.
-func GetCustomDKIMVerifierWrapper(mailType string) (*CustomDKIMVerifierWrapper[emparams.Mod1e4096], error) {
-	var template string
-	switch mailType {
-	case "gmail":
-		template = GmailTemplate
-	case "outlook":
-		template = OutlookTemplate
-	case "foxmail":
-		template = FoxmailTemplate
-	case "icloud":
-		template = ICloudTemplate
+// DKIMVerifier is a DKIM verifier circuit whose emulated arithmetic is sized for the provider's RSA key.
+type DKIMVerifier interface {
+	frontend.Circuit
+	// KeyBits returns the RSA modulus size supported by the circuit, it is 256 for Ed25519 keys.
+	KeyBits() int
+	// NewAssignment creates an assignment of the circuit from the email message and DNS TXT record.
+	NewAssignment(message string, txtRecord string) (frontend.Circuit, error)
+	// NewKeySetAssignment creates an assignment of the circuit in the key set mode.
+	NewKeySetAssignment(message string, txtRecord string, keySet *KeySet) (frontend.Circuit, error)
+	// NewSaltedAssignment creates an assignment of the circuit in the salted sender mode,
+	// keySet is nil outside the key set mode.
+	NewSaltedAssignment(message string, txtRecord string, keySet *KeySet, salt []byte) (frontend.Circuit, error)
+	// PublicKeyHash computes the public key hash output of the circuit from the DNS TXT record.
+	PublicKeyHash(txtRecord string) ([]*big.Int, error)
+	// SetBinding binds the assignment to the caller address and the chain ID, see Binding.
+	SetBinding(binding *big.Int)
+}
+
+// KeyBits returns the RSA modulus size supported by the circuit, it is 256 for Ed25519 keys.
+func (c *CustomDKIMVerifierWrapper[T]) KeyBits() int {
+	return keyBytes[T]() * 8
+}
+
+// NewAssignment creates an assignment of the circuit from the email message and DNS TXT record.
+func (c *CustomDKIMVerifierWrapper[T]) NewAssignment(message string, txtRecord string) (frontend.Circuit, error) {
+	if c.Config.KeySetDepth != 0 {
+		return nil, errors.New("the circuit in the key set mode needs the key set of the assignment")
+	}
+	return NewAssignment[T](message, txtRecord, c)
+}
+
+// NewKeySetAssignment creates an assignment of the circuit in the key set mode, the DKIM key must be in the key set.
+func (c *CustomDKIMVerifierWrapper[T]) NewKeySetAssignment(message string, txtRecord string, keySet *KeySet) (frontend.Circuit, error) {
+	if c.Config.KeySetDepth == 0 {
+		return nil, errors.New("the circuit is not in the key set mode")
+	}
+	assignment, err := NewAssignment[T](message, txtRecord, c)
+	if err != nil {
+		return nil, err
+	}
+	return c.withKeyPath(assignment.(*CustomDKIMVerifierWrapper[T]), txtRecord, keySet)
+}
+
+// NewSaltedAssignment creates an assignment of the circuit in the salted sender mode with the SaltSize bytes salt,
+// keySet is nil outside the key set mode.
+func (c *CustomDKIMVerifierWrapper[T]) NewSaltedAssignment(message string, txtRecord string, keySet *KeySet, salt []byte) (frontend.Circuit, error) {
+	if !c.Config.SenderSalted {
+		return nil, errors.New("the circuit is not in the salted sender mode")
+	}
+	if (c.Config.KeySetDepth != 0) != (keySet != nil) {
+		return nil, errors.New("key set does not match the circuit")
+	}
+	if len(salt) != SaltSize {
+		return nil, fmt.Errorf("sender salt must be %d bytes", SaltSize)
+	}
+	assignment, err := newAssignment[T](message, txtRecord, c, salt)
+	if err != nil {
+		return nil, err
+	}
+	result := assignment.(*CustomDKIMVerifierWrapper[T])
+	if keySet == nil {
+		return result, nil
+	}
+	return c.withKeyPath(result, txtRecord, keySet)
+}
+
+// withKeyPath fills the key root and the key path of the assignment in the key set mode.
+func (c *CustomDKIMVerifierWrapper[T]) withKeyPath(assignment *CustomDKIMVerifierWrapper[T], txtRecord string, keySet *KeySet) (frontend.Circuit, error) {
+	if keySet.Depth() != c.Config.KeySetDepth || keySet.Hash() != c.Config.Commitment {
+		return nil, errors.New("key set does not match the circuit")
+	}
+	keyHash, err := c.PublicKeyHash(txtRecord)
+	if err != nil {
+		return nil, err
+	}
+	index, siblings, err := keySet.Path(keyHash[0])
+	if err != nil {
+		return nil, err
+	}
+	assignment.KeyRoot = []frontend.Variable{keySet.Root()}
+	assignment.KeyPath = KeyPath{
+		Index:    index,
+		Siblings: BigIntsToFrontVariable(siblings),
+	}
+	return assignment, nil
+}
+
+// SetBinding binds the assignment to the caller address and the chain ID, see Binding.
+// Assignments are created with the binding 0, which no contract accepts.
+func (c *CustomDKIMVerifierWrapper[T]) SetBinding(binding *big.Int) {
+	c.Binding = binding
+}
+
+// PublicKeyHash computes the public key hash output of the circuit from the DNS TXT record.
+func (c *CustomDKIMVerifierWrapper[T]) PublicKeyHash(txtRecord string) ([]*big.Int, error) {
+	return PublicKeyHash(txtRecord, c.KeyBits(), c.Config.Commitment)
+}
+
+// GetCustomDKIMVerifierWrapper returns a DKIM verifier circuit template for the specified mail type
+// of the DefaultRegistry.
+func GetCustomDKIMVerifierWrapper(mailType string, opts ...Option) (DKIMVerifier, error) {
+	return DefaultRegistry.Verifier(mailType, opts...)
+}
+
+// NewCustomDKIMVerifierWrapper returns a DKIM verifier circuit template shaped by the mail template.
+func NewCustomDKIMVerifierWrapper(template MailTemplate, opts ...Option) (DKIMVerifier, error) {
+	cfg := newVerifierConfig(append(slices.Clone(template.Options), opts...)...)
+	if cfg.KeySetDepth != 0 && cfg.Commitment == CommitmentSHA256 {
+		return nil, errors.New("key set requires an algebraic commitment hash")
+	}
+	if cfg.SubjectCommand != "" && (cfg.revealedIndex("subject") == -1 || cfg.CommandCapacity <= 0) {
+		return nil, errors.New("subject command requires the revealed subject header and a capacity")
+	}
+	if cfg.SenderCapacity < 0 || (cfg.SenderCapacity != 0 && cfg.revealedIndex("from") == -1) {
+		return nil, errors.New("sender address requires the revealed from header")
+	}
+	if cfg.SenderSalted && cfg.SenderCapacity == 0 {
+		return nil, errors.New("salted sender requires the sender address or domain")
+	}
+	if cfg.DateTimestamp && cfg.revealedIndex("date") == -1 {
+		return nil, errors.New("date timestamp requires the revealed date header")
+	}
+	if cfg.DomainAlignment != AlignmentNone && cfg.revealedIndex("from") == -1 {
+		return nil, errors.New("domain alignment requires the revealed from header")
+	}
+	// DKIM signs with rsa-sha1, rsa-sha256 and ed25519-sha256, the other hash functions of the RSA gadget
+	// have no DKIM signing algorithm.
+	if cfg.Hash != 0 && cfg.Hash != crypto.SHA1 && cfg.Hash != crypto.SHA256 {
+		return nil, fmt.Errorf("hash function %v has no DKIM signing algorithm, it must be SHA-1 or SHA-256", cfg.Hash)
+	}
+	if cfg.Canonicalization != "" {
+		name, err := algorithm.CanonName(cfg.Canonicalization)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Canonicalization = name
+	}
+	if cfg.SignatureDomain != "" {
+		domain, err := normalizeDomain(cfg.SignatureDomain)
+		if err != nil || domain != cfg.SignatureDomain || len(domain) > DomainCapacity {
+			return nil, fmt.Errorf("invalid signature domain %q", cfg.SignatureDomain)
+		}
+	}
+	// The universal template is built from the smallest signed headers and resized to its capacities.
+	if template.Capacities != nil {
+		err := template.Capacities.check()
+		if err != nil {
+			return nil, err
+		}
+		template.Header, err = capacityHeader(cfg, template.KeyBits)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// An ed25519-sha256 template selects the Ed25519 mode, its key size is fixed.
+	signature, err := emailSignature(template.Header)
+	if err != nil {
+		return nil, err
+	}
+	if signature.Algo().KeyType() == "ed25519" {
+		if cfg.FixedExponent != 0 {
+			return nil, errors.New("fixed exponent requires an RSA key")
+		}
+		cfg.Ed25519 = true
+		return newVerifier[Ed25519Fp](template.Header, template.Capacities, cfg)
+	}
+	switch template.KeyBits {
+	case 1024:
+		return newVerifier[Mod1e1024](template.Header, template.Capacities, cfg)
+	case 2048:
+		return newVerifier[Mod1e2048](template.Header, template.Capacities, cfg)
+	case 4096:
+		return newVerifier[emparams.Mod1e4096](template.Header, template.Capacities, cfg)
 	default:
-		return nil, errors.New("unknown mail type")
+		return nil, fmt.Errorf("unsupported RSA key size %d", template.KeyBits)
 	}
-	result, err := newCircuit[emparams.Mod1e4096](template, rsaPubkeyTemplate)
+}
+
+// newVerifier creates the circuit template of the mail header with the emulated field T,
+// capacities, when not nil, replace the lengths of the header.
+func newVerifier[T emulated.FieldParams](header string, capacities *Capacities, cfg VerifierConfig) (DKIMVerifier, error) {
+	pubkeyTemplate := ed25519PubkeyTemplate
+	if !cfg.Ed25519 {
+		var err error
+		pubkeyTemplate, err = rsaPubkeyTemplate(keyBytes[T]())
+		if err != nil {
+			return nil, err
+		}
+	}
+	result, err := newCircuit[T](header, pubkeyTemplate, cfg)
 	if err != nil {
 		return nil, err
 	}
+	if capacities != nil {
+		result.resize(capacities)
+	}
+	// The subject of the template only needs to bound the command.
+	if cfg.SubjectCommand != "" {
+		index := cfg.revealedIndex("subject")
+		length := commandHeaderLength(cfg.SubjectCommand, cfg.CommandCapacity)
+		if len(result.Header.SpecifyData[index].Slice) < length {
+			result.Header.SpecifyData[index] = BytesToFixPadding(nil, false, length)
+		}
+	}
 	return result, nil
 }
 
 // NewAssignment creates a new assignment for the DKIM verifier circuit.
 func NewAssignment[T emulated.FieldParams](message string, txtRecord string, templateCircuit *CustomDKIMVerifierWrapper[T]) (frontend.Circuit, error) {
-	assignment, err := newCircuit[T](message, txtRecord)
+	if templateCircuit.Config.SenderSalted {
+		return nil, errors.New("the circuit in the salted sender mode needs the salt of the assignment")
+	}
+	return newAssignment[T](message, txtRecord, templateCircuit, nil)
+}
+
+// newAssignment creates a new assignment for the DKIM verifier circuit, salt is the sender salt in the salted sender mode.
+func newAssignment[T emulated.FieldParams](message string, txtRecord string, templateCircuit *CustomDKIMVerifierWrapper[T], salt []byte) (frontend.Circuit, error) {
+	if templateCircuit.Config.FixedExponent != 0 {
+		rsaPubKey, err := parseRSAPublicKey(txtRecord)
+		if err != nil {
+			return nil, err
+		}
+		if rsaPubKey.E != templateCircuit.Config.FixedExponent {
+			return nil, fmt.Errorf("RSA exponent %d is not supported by the circuit with fixed exponent %d", rsaPubKey.E, templateCircuit.Config.FixedExponent)
+		}
+	}
+	assignment, err := newCircuit[T](message, txtRecord, templateCircuit.Config)
 	if err != nil {
 		return nil, err
 	}
 	// Pad 0.
-	prefixDataPaddingLength := len(templateCircuit.Header.PrefixData.Slice) - len(assignment.Header.PrefixData.Slice)
-	specifyDataPaddingLength := len(templateCircuit.Header.SpecifyData.Slice) - len(assignment.Header.SpecifyData.Slice)
-	suffixDataPaddingLength := len(templateCircuit.Header.SuffixData.Slice) - len(assignment.Header.SuffixData.Slice)
-	sigPrefixPaddingLength := len(templateCircuit.Signature.SigPrefix.Slice) - len(assignment.Signature.SigPrefix.Slice)
-	sigSuffixPaddingLength := len(templateCircuit.Signature.SigSuffix.Slice) - len(assignment.Signature.SigSuffix.Slice)
-
-	if prefixDataPaddingLength < 0 {
-		return nil, errors.New("prefix data size is too big")
+	if len(assignment.Header.HiddenData) != len(templateCircuit.Header.HiddenData) {
+		return nil, errors.New("revealed headers do not match the circuit")
+	}
+	for i := range assignment.Header.HiddenData {
+		assignment.Header.HiddenData[i], err = padToTemplate(templateCircuit.Header.HiddenData[i], assignment.Header.HiddenData[i], "hidden data")
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range assignment.Header.SpecifyData {
+		assignment.Header.SpecifyData[i], err = padToTemplate(templateCircuit.Header.SpecifyData[i], assignment.Header.SpecifyData[i], "specify data")
+		if err != nil {
+			return nil, err
+		}
 	}
-	if specifyDataPaddingLength < 0 {
-		return nil, errors.New("specify data size is too big")
+	assignment.Signature.SigPrefix, err = padToTemplate(templateCircuit.Signature.SigPrefix, assignment.Signature.SigPrefix, "sigPrefix data")
+	if err != nil {
+		return nil, err
+	}
+	assignment.Signature.SigSuffix, err = padToTemplate(templateCircuit.Signature.SigSuffix, assignment.Signature.SigSuffix, "sigSuffix data")
+	if err != nil {
+		return nil, err
 	}
-	if suffixDataPaddingLength < 0 {
-		return nil, errors.New("suffix data size is too big")
+	if templateCircuit.Config.SubjectCommand != "" {
+		arg, err := SubjectCommand(message, templateCircuit.Config.SubjectCommand)
+		if err != nil {
+			return nil, err
+		}
+		if len(arg) > templateCircuit.Config.CommandCapacity {
+			return nil, errors.New("command argument size is too big")
+		}
+		command := make([]byte, templateCircuit.Config.CommandCapacity)
+		copy(command, arg)
+		assignment.Command = BigIntsToFrontVariable(PackBytes(command))
 	}
-	if sigPrefixPaddingLength < 0 {
-		return nil, errors.New("sigPrefix data size is too big")
+	// The masks of the t=, d= and s= tags index the trimmed DKIM-Signature header built by the circuit.
+	trimmedHeader, err := signatureTrimmedHeader(message)
+	if err != nil {
+		return nil, err
 	}
-	if sigSuffixPaddingLength < 0 {
-		return nil, errors.New("sigSuffix data size is too big")
+	if templateCircuit.Config.DateTimestamp {
+		canonHeader, err := signedHeader(message, "date")
+		if err != nil {
+			return nil, err
+		}
+		timestamp, err := algorithm.ParseDate(string(canonHeader))
+		if err != nil {
+			return nil, err
+		}
+		assignment.Timestamp = []frontend.Variable{timestamp}
+	} else if templateCircuit.Config.Timestamp {
+		timestamp, err := signatureTimestamp([]byte(trimmedHeader))
+		if err != nil {
+			return nil, err
+		}
+		assignment.TimestampMask, err = SignatureTimestampMask([]byte(trimmedHeader), templateCircuit.trimmedHeaderLength())
+		if err != nil {
+			return nil, err
+		}
+		assignment.Timestamp = []frontend.Variable{timestamp}
 	}
-	prefixDataZeroPadding := BytesToFrontVariable(make([]byte, prefixDataPaddingLength))
-	specifyDataZeroPadding := BytesToFrontVariable(make([]byte, specifyDataPaddingLength))
-	suffixDataZeroPadding := BytesToFrontVariable(make([]byte, suffixDataPaddingLength))
-
-	assignment.Header.PrefixData.Slice = append(prefixDataZeroPadding, assignment.Header.PrefixData.Slice...)
-	assignment.Header.SpecifyData.Slice = append(specifyDataZeroPadding, assignment.Header.SpecifyData.Slice...)
-	assignment.Header.SuffixData.Slice = append(suffixDataZeroPadding, assignment.Header.SuffixData.Slice...)
-
-	assignment.Header.PrefixData.Padding = frontend.Variable(prefixDataPaddingLength - 1)
-	assignment.Header.SpecifyData.Padding = frontend.Variable(specifyDataPaddingLength - 1)
-	assignment.Header.SuffixData.Padding = frontend.Variable(suffixDataPaddingLength - 1)
-
-	sigPrefixZeroPadding := BytesToFrontVariable(make([]byte, sigPrefixPaddingLength))
-	assignment.Signature.SigPrefix.Slice = append(sigPrefixZeroPadding, assignment.Signature.SigPrefix.Slice...)
-	assignment.Signature.SigPrefix.Padding = frontend.Variable(sigPrefixPaddingLength - 1)
+	if templateCircuit.Config.needsSignatureDomain() {
+		assignment.SignatureDomainMask, err = SignatureDomainMask([]byte(trimmedHeader), templateCircuit.trimmedHeaderLength())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if templateCircuit.Config.SignatureDomain != "" {
+		sigDomain, err := signatureDomain([]byte(trimmedHeader))
+		if err != nil {
+			return nil, err
+		}
+		if sigDomain != templateCircuit.Config.SignatureDomain {
+			return nil, fmt.Errorf("signature domain %s is not %s", sigDomain, templateCircuit.Config.SignatureDomain)
+		}
+	}
+	if templateCircuit.Config.KeyName {
+		sigDomain, err := signatureDomain([]byte(trimmedHeader))
+		if err != nil {
+			return nil, err
+		}
+		selector, err := signatureSelector([]byte(trimmedHeader))
+		if err != nil {
+			return nil, err
+		}
+		keyName, err := KeyNameCommitment(templateCircuit.Config.Commitment, sigDomain, selector)
+		if err != nil {
+			return nil, err
+		}
+		assignment.SelectorMask, err = SignatureSelectorMask([]byte(trimmedHeader), templateCircuit.trimmedHeaderLength())
+		if err != nil {
+			return nil, err
+		}
+		assignment.KeyName = BigIntsToFrontVariable(keyName)
+	}
+	if templateCircuit.Config.SenderCapacity == 0 && templateCircuit.Config.DomainAlignment == AlignmentNone {
+		return assignment, nil
+	}
+	canonHeader, err := signedHeader(message, "from")
+	if err != nil {
+		return nil, err
+	}
+	address, err := senderAddress(canonHeader)
+	if err != nil {
+		return nil, err
+	}
+	domain := address[strings.IndexByte(address, '@')+1:]
+	index := templateCircuit.Config.revealedIndex("from")
+	assignment.SenderMask, err = SenderMask(canonHeader, len(templateCircuit.Header.SpecifyData[index].Slice))
+	if err != nil {
+		return nil, err
+	}
+	if templateCircuit.Config.SenderCapacity != 0 {
+		if templateCircuit.Config.SenderDomainOnly {
+			address = domain
+		}
+		sender, err := senderCommitment(templateCircuit.Config.Commitment, address, templateCircuit.Config.SenderCapacity, salt)
+		if err != nil {
+			return nil, err
+		}
+		if templateCircuit.Config.SenderSalted {
+			assignment.SenderSalt = BytesToFrontVariable(salt)
+		}
+		assignment.Sender = BigIntsToFrontVariable(sender)
+	}
+	if templateCircuit.Config.DomainAlignment != AlignmentNone {
+		sigDomain, err := signatureDomain([]byte(trimmedHeader))
+		if err != nil {
+			return nil, err
+		}
+		err = CheckDomainAlignment(domain, sigDomain, templateCircuit.Config.DomainAlignment)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return assignment, nil
+}
 
-	sigSuffixZeroPadding := BytesToFrontVariable(make([]byte, sigSuffixPaddingLength))
-	assignment.Signature.SigSuffix.Slice = append(sigSuffixZeroPadding, assignment.Signature.SigSuffix.Slice...)
-	assignment.Signature.SigSuffix.Padding = frontend.Variable(sigSuffixPaddingLength - 1)
+// trimmedHeaderLength returns the length of the trimmed header slice built by GetTrimmedHeader.
+func (c *CustomDKIMVerifierWrapper[T]) trimmedHeaderLength() int {
+	return len(c.Signature.SigPrefix.Slice) + base64.StdEncoding.EncodedLen(len(c.Signature.BodyHash)) + len(c.Signature.SigSuffix.Slice)
+}
 
+// padToTemplate pads the assignment slice with leading zeros to the length of the template slice.
+func padToTemplate(template PaddingSlice, assignment PaddingSlice, name string) (PaddingSlice, error) {
+	paddingLength := len(template.Slice) - len(assignment.Slice)
+	if paddingLength < 0 {
+		return PaddingSlice{}, fmt.Errorf("%s size is too big", name)
+	}
+	assignment.Slice = append(BytesToFrontVariable(make([]byte, paddingLength)), assignment.Slice...)
+	assignment.Padding = frontend.Variable(paddingLength - 1)
 	return assignment, nil
 }
 
 // newCircuit creates a new DKIM verifier circuit from the email message and DNS TXT record.
-func newCircuit[T emulated.FieldParams](message string, txtRecord string) (*CustomDKIMVerifierWrapper[T], error) {
+func newCircuit[T emulated.FieldParams](message string, txtRecord string, cfg VerifierConfig) (*CustomDKIMVerifierWrapper[T], error) {
 	email := algorithm.ParseEmail(message)
-	var signatureHeader string
-	fromHeaderIndex := -1
-	for index, header := range email.Headers() {
-		// We don't support DKIM-Signature headers signing other DKIM-Signature.
-		if algorithm.IsFromHeader(header) {
-			fromHeaderIndex = index
-		}
-		// Check and find DKIM-Signature header.
-		if algorithm.IsSignatureHeader(header) {
-			if signatureHeader != "" {
-				return nil, errors.New("multiple DKIM headers")
-			}
-			signatureHeader = header
-		}
+	signature, err := emailSignature(message)
+	if err != nil {
+		return nil, err
 	}
-	if fromHeaderIndex == -1 {
-		return nil, errors.New("no from address header found")
+	// The template fixes the key type, the hash function and the canonicalization, assignments must use the same ones.
+	if cfg.Ed25519 != (signature.Algo().KeyType() == "ed25519") {
+		return nil, errors.New("signature algorithm does not match the circuit")
 	}
-	if signatureHeader == "" {
-		return nil, errors.New("no DKIM header found")
+	if cfg.Hash == 0 {
+		cfg.Hash = signature.Algo().Hash()
+	} else if cfg.Hash != signature.Algo().Hash() {
+		return nil, errors.New("signature algorithm does not match the circuit")
 	}
-	signature, err := algorithm.ParseSignature(signatureHeader)
-	if err != nil {
-		return nil, err
+	if cfg.Canonicalization != "" && cfg.Canonicalization != signature.Canon().Name() {
+		return nil, fmt.Errorf("canonicalization %s does not match the circuit %s", signature.Canon().Name(), cfg.Canonicalization)
+	}
+	if cfg.parsesHeaders() && !strings.HasPrefix(signature.Canon().Name(), "relaxed/") {
+		return nil, fmt.Errorf("canonicalization %s is not supported by the header parsing of the circuit, it requires relaxed headers", signature.Canon().Name())
 	}
 	signatureHeaderNames := make([]string, len(signature.HeaderNames()))
 	for i, name := range signature.HeaderNames() {
 		signatureHeaderNames[i] = strings.ToLower(name)
 	}
 	signedHeaders := algorithm.ExtractHeaders(email.Headers(), signatureHeaderNames)
-	predixData := make([]byte, 0)
-	specifyData := make([]byte, 0)
-	suffixData := make([]byte, 0)
-	for index, header := range signedHeaders {
-		if index < fromHeaderIndex {
-			predixData = append(predixData, []byte(signature.Canon().Header()(header))...)
-		}
-		if index == fromHeaderIndex {
-			specifyData = append(specifyData, []byte(signature.Canon().Header()(header))...)
-		}
-		if index > fromHeaderIndex {
-			suffixData = append(suffixData, []byte(signature.Canon().Header()(header))...)
+	// Split the signed headers at the revealed ones, which are taken in the order of the signed headers.
+	hiddenData := [][]byte{make([]byte, 0)}
+	specifyData := make([][]byte, 0)
+	for _, header := range signedHeaders {
+		canonHeader := []byte(signature.Canon().Header()(header))
+		if len(specifyData) < len(cfg.RevealedHeaders) && algorithm.IsHeader(header, cfg.RevealedHeaders[len(specifyData)]) {
+			specifyData = append(specifyData, canonHeader)
+			hiddenData = append(hiddenData, make([]byte, 0))
+			continue
 		}
+		hiddenData[len(hiddenData)-1] = append(hiddenData[len(hiddenData)-1], canonHeader...)
 	}
-	trimmedHeader := signature.Canon().Header()(signature.TrimmedHeader())
+	if len(specifyData) < len(cfg.RevealedHeaders) {
+		return nil, fmt.Errorf("no signed %s header found", cfg.RevealedHeaders[len(specifyData)])
+	}
+	trimmedHeader := signedTrimmedHeader(signature)
 	sigPrefix := trimmedHeader[0 : strings.Index(trimmedHeader, "bh=")+3]
 	sigSuffix := trimmedHeader[strings.Index(trimmedHeader, "bh=")+3+len(base64.StdEncoding.EncodeToString(signature.BodyHash())) : strings.Index(trimmedHeader, "b=")+2]
-	bodyHash := [32]frontend.Variable{}
-	for i := range bodyHash {
-		bodyHash[i] = signature.BodyHash()[i]
+	// N, E and the signature are sized to the RSA key width of the circuit, an Ed25519 signature is always 64 bytes.
+	publicKey := &PublicKey[T]{N: emulated.ValueOf[T](0), E: emulated.ValueOf[T](0)}
+	ed25519Key := make([]Ed25519PublicKey, 0)
+	width := keyBytes[T]()
+	var pubKeyHash []*big.Int
+	if cfg.Ed25519 {
+		key, err := parseEd25519PublicKey(txtRecord)
+		if err != nil {
+			return nil, err
+		}
+		point, err := newEd25519PublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		ed25519Key = append(ed25519Key, point)
+		width = ed25519.SignatureSize
+		if len(signature.Signature()) != width {
+			return nil, errors.New("Ed25519 signature must be 64 bytes")
+		}
+		pubKeyHash, err = CommitPublicInputs(cfg.Commitment, key)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		rsaPubKey, err := parseRSAPublicKey(txtRecord)
+		if err != nil {
+			return nil, err
+		}
+		// The encoded message is built with the byte length of the circuit key, smaller keys would not verify.
+		if (rsaPubKey.N.BitLen()+7)/8 != width {
+			return nil, fmt.Errorf("RSA key size %d does not match the circuit key size %d", rsaPubKey.N.BitLen(), width*8)
+		}
+		publicKey.N = emulated.ValueOf[T](rsaPubKey.N)
+		publicKey.E = emulated.ValueOf[T](rsaPubKey.E)
+		pubKeyHash, err = publicKeyHash(rsaPubKey, width, cfg.Commitment)
+		if err != nil {
+			return nil, err
+		}
 	}
-	// From DNS txt record to RSA pubkey.
-	key := algorithm.ParsePubkey(txtRecord)
-	pubKey, err := x509.ParsePKIXPublicKey(key.Key())
+	if len(signature.Signature()) > width {
+		return nil, errors.New("signature size is too big")
+	}
+	sigContent := make([]byte, width)
+	copy(sigContent[width-len(signature.Signature()):], signature.Signature())
+	// Compute publicInputHash and pubKeyHash.
+	pubInputData := [][]byte{signature.BodyHash()}
+	for i, data := range specifyData {
+		if cfg.hashedHeader(i) {
+			pubInputData = append(pubInputData, GetHash(data))
+		}
+	}
+	pubInputHash, err := CommitPublicInputs(cfg.Commitment, pubInputData...)
+	if err != nil {
+		return nil, err
+	}
+	sigNullifier, err := Nullifier(cfg.Commitment, signature.Signature(), width*8)
 	if err != nil {
 		return nil, err
 	}
-	// Compute publicInputHash.
-	nBytes := pubKey.(*rsa.PublicKey).N.FillBytes(make([]byte, 512))
-	eBytes := new(big.Int).SetInt64(int64(pubKey.(*rsa.PublicKey).E)).FillBytes(make([]byte, 512))
-	fromHash := GetHash(specifyData)
-	pubInputHash := GetHash(nBytes, eBytes, signature.BodyHash(), fromHash)
+	// The command argument is filled by NewAssignment.
+	command := make([]frontend.Variable, (cfg.CommandCapacity+PubInputChunkSize-1)/PubInputChunkSize)
+	for i := range command {
+		command[i] = 0
+	}
+	if cfg.SubjectCommand == "" {
+		command = command[:0]
+	}
+	// The sender commitment, the salt and the masks of the From header and of the d= domain are filled by NewAssignment.
+	sender := make([]frontend.Variable, 0)
+	senderMask := make([]frontend.Variable, 0)
+	senderSalt := make([]frontend.Variable, 0)
+	signatureDomainMask := make([]frontend.Variable, 0)
+	if cfg.SenderCapacity != 0 {
+		sender = BytesToFrontVariable(make([]byte, commitmentSize(cfg.Commitment)))
+	}
+	if fromIndex := cfg.revealedIndex("from"); fromIndex != -1 && (cfg.SenderCapacity != 0 || cfg.DomainAlignment != AlignmentNone) {
+		senderMask = BytesToFrontVariable(make([]byte, len(specifyData[fromIndex])))
+	}
+	if cfg.SenderSalted {
+		senderSalt = BytesToFrontVariable(make([]byte, SaltSize))
+	}
+	if cfg.needsSignatureDomain() {
+		signatureDomainMask = BytesToFrontVariable(make([]byte, len(trimmedHeader)))
+	}
+	// The key name and the mask of the s= selector are filled by NewAssignment.
+	keyName := make([]frontend.Variable, 0)
+	selectorMask := make([]frontend.Variable, 0)
+	if cfg.KeyName {
+		keyName = BytesToFrontVariable(make([]byte, commitmentSize(cfg.Commitment)))
+		selectorMask = BytesToFrontVariable(make([]byte, len(trimmedHeader)))
+	}
+	// The signing time and the mask of the t= tag are filled by NewAssignment.
+	timestamp := make([]frontend.Variable, 0)
+	timestampMask := make([]frontend.Variable, 0)
+	if cfg.Timestamp {
+		timestamp = append(timestamp, 0)
+	}
+	if cfg.Timestamp && !cfg.DateTimestamp {
+		timestampMask = BytesToFrontVariable(make([]byte, len(trimmedHeader)))
+	}
+	// In the key set mode the key hash stays private, the key path is filled by NewKeySetAssignment.
+	keyRoot := make([]frontend.Variable, 0)
+	keyPath := KeyPath{Index: 0, Siblings: make([]frontend.Variable, cfg.KeySetDepth)}
+	if cfg.KeySetDepth != 0 {
+		pubKeyHash = nil
+		keyRoot = append(keyRoot, 0)
+		for i := range keyPath.Siblings {
+			keyPath.Siblings[i] = 0
+		}
+	}
 	return &CustomDKIMVerifierWrapper[T]{
-		PublicKey: &PublicKey[T]{
-			N: emulated.ValueOf[T](pubKey.(*rsa.PublicKey).N),
-			E: emulated.ValueOf[T](pubKey.(*rsa.PublicKey).E),
-		},
+		PublicKey:  publicKey,
+		Ed25519Key: ed25519Key,
 		Header: CustomEmailHeader{
-			PrefixData:  BytesToPadding(predixData, false, -1),
-			SpecifyData: BytesToPadding(specifyData, false, -1),
-			SuffixData:  BytesToPadding(suffixData, false, -1),
+			HiddenData:  bytesToPaddings(hiddenData),
+			SpecifyData: bytesToPaddings(specifyData),
 		},
 		Signature: EmailSig{
 			SigPrefix:  BytesToPadding([]byte(sigPrefix), false, -1),
-			BodyHash:   bodyHash,
+			BodyHash:   BytesToFrontVariable(signature.BodyHash()),
 			SigSuffix:  BytesToPadding([]byte(sigSuffix), false, -1),
-			SigContent: BytesToFrontVariable(signature.Signature()),
+			SigContent: BytesToFrontVariable(sigContent),
 		},
-		PubInputHash: BytesToFrontVariable(pubInputHash),
+		PubInputHash:        BigIntsToFrontVariable(pubInputHash),
+		PubKeyHash:          BigIntsToFrontVariable(pubKeyHash),
+		KeyRoot:             keyRoot,
+		KeyName:             keyName,
+		KeyPath:             keyPath,
+		Command:             command,
+		Sender:              sender,
+		SenderMask:          senderMask,
+		SenderSalt:          senderSalt,
+		SignatureDomainMask: signatureDomainMask,
+		SelectorMask:        selectorMask,
+		Timestamp:           timestamp,
+		TimestampMask:       timestampMask,
+		Binding:             0,
+		Nullifier:           sigNullifier,
+		Config:              cfg,
 	}, nil
 }
+
+// emailSignature finds and parses the single DKIM-Signature header of the email message.
+func emailSignature(message string) (*algorithm.Signature, error) {
+	var signatureHeader string
+	for _, header := range algorithm.ParseEmail(message).Headers() {
+		// We don't support DKIM-Signature headers signing other DKIM-Signature.
+		// Check and find DKIM-Signature header.
+		if algorithm.IsSignatureHeader(header) {
+			if signatureHeader != "" {
+				return nil, errors.New("multiple DKIM headers")
+			}
+			signatureHeader = header
+		}
+	}
+	if signatureHeader == "" {
+		return nil, errors.New("no DKIM header found")
+	}
+	return algorithm.ParseSignature(signatureHeader)
+}
+
+// parseRSAPublicKey parses the RSA public key from the DNS TXT record.
+func parseRSAPublicKey(txtRecord string) (*rsa.PublicKey, error) {
+	key := algorithm.ParsePubkey(txtRecord)
+	pubKey, err := x509.ParsePKIXPublicKey(key.Key())
+	if err != nil {
+		return nil, err
+	}
+	rsaPubKey, ok := pubKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not an RSA public key")
+	}
+	return rsaPubKey, nil
+}
+
+// PublicKeyHash computes the public key hash of the DNS TXT record with the commitment hash h.
+// N and E are encoded big-endian with the byte length of a keyBits RSA modulus,
+// so contracts can keep a (domain, keyHash) registry of the DKIM keys.
+// An Ed25519 key is committed by its 32-byte encoding, keyBits is then ignored.
+func PublicKeyHash(txtRecord string, keyBits int, h CommitmentHash) ([]*big.Int, error) {
+	if algorithm.ParsePubkey(txtRecord).KeyType() == "ed25519" {
+		key, err := parseEd25519PublicKey(txtRecord)
+		if err != nil {
+			return nil, err
+		}
+		return CommitPublicInputs(h, key)
+	}
+	rsaPubKey, err := parseRSAPublicKey(txtRecord)
+	if err != nil {
+		return nil, err
+	}
+	return publicKeyHash(rsaPubKey, (keyBits+7)/8, h)
+}
+
+// publicKeyHash computes the public key hash with N and E encoded in width bytes.
+func publicKeyHash(pubKey *rsa.PublicKey, width int, h CommitmentHash) ([]*big.Int, error) {
+	if (pubKey.N.BitLen()+7)/8 != width {
+		return nil, fmt.Errorf("RSA key size %d does not match the key size %d", pubKey.N.BitLen(), width*8)
+	}
+	nBytes := pubKey.N.FillBytes(make([]byte, width))
+	eBytes := new(big.Int).SetInt64(int64(pubKey.E)).FillBytes(make([]byte, width))
+	return CommitPublicInputs(h, nBytes, eBytes)
+}
@@ -79,6 +79,85 @@ Subject: zkemail test
 Date: Tue, 4 Nov 2025 16:28:38 +0800
 dkim-signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=foxmail.com; s=s201512; t=1762244918; bh=puPE4wrCV5YcrcfuFwepbL9s4gzEO/Omu6K0zc+lG5k=; h=From:To:Subject:Date; b=puBeLAmUrZcLTca/kAoDQaW1lUTidBFWtU5oEwIA3dJoeF/8wol9exglsHJFq58budhpmES0VTMpCr4v3rb4TH0gJ+r/Z3k1009nMQlBh3gTWJAG6LUgvXDxlQQBZRM4NlhrgenWw7yebQlbltmOfdY3Uy/mkiidj8fMNEfI3I4=`)
 
+var YahooTestData = utils.FixupNewlines(`Date: Fri, 31 Oct 2025 07:02:30 +0000 (UTC)
+From: Alice Example <alice.example@yahoo.com>
+To: "bob@example.org" <bob@example.org>
+Subject: zkemail test
+References: <1869243613.4218071.1761894150123.ref@mail.yahoo.com>
+DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=yahoo.com; s=s2048; t=1761894150; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; h=Date:From:To:Subject:References:From:Subject:Reply-To; b=Ij4xInGk04s2xm9V6zpM9msPHPsMya3mmgZR2HZUdogL3juJcU/90Sp3H5dPFd9zDAl4ir1wqoxfFuVKFsQF3kF09q9TY85roXKVtnQeDDGe3720HElE+OIC/s0zbiWsUIwv5FSweeJku5dWnDI6Ui/H8f79/wgZbcGJ/VHy3B6vNsXovhdnUElYSZVe9NQwtpje6n0p+csP1CTvPVygr9is1xfqPPJ2y82zzotUcATBnwjYa7yrkMIIKRhxshY/vdmAIHiswg/ndxYIazSKu8U/1uFYIFW8GVH/TZ8WaFckAUw4vvxyUaOVCcL/eTggR039l60AIB4IzLPx6LDUwA==`)
+
+var ProtonTestData = utils.FixupNewlines(`Date: Fri, 31 Oct 2025 07:02:30 +0000
+To: bob@example.org
+From: Alice Example <alice.example@proton.me>
+Subject: zkemail test
+Message-ID: <Xq3vF0d2Lr5Yk8Jp1sT6wZ9bN4mC7hQ2eA5uG8iK1oP3rD6fV9xS0yB2nM4lJ7tW5cE8hR1aU3gZ6q=@proton.me>
+Feedback-ID: 123456789:user:proton
+DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=proton.me;
+	s=protonmail; t=1761894150; x=1762153350;
+	bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=;
+	h=Date:To:From:Subject:Message-ID:Feedback-ID:From:To:Cc:Date:Subject:
+	 Reply-To:Feedback-ID:Message-ID:BIMI-Selector;
+	b=N6XmQoEYGEneV1TnSucApeb1K7UrlOOvwf69/SJnrmLb8Bw5YcGTMHb2sO/oYeYPMGWMbB
+	 MUrN7QTun2xOEBmo7x8jVYL7pJnMIMs1PJBw/L2ENVVXXdf3QRqPdi/PwgvP1yFd1/PVcY
+	 Wl6yfZNXIYLn/08Ph0Jeld9PHhiA5felrtXoJG1XU8PpqTBd0Cl8Mj7FuUJ3LkouyXTi8w
+	 Yha5kdoRlHygQYz72FTGXmiU0KMo3/tbQcBtriqtE4wZ0V1flU+vwyeoGR8z11nFOCsxTl
+	 PRSqlkkc41NlnVD/12x45Dddd+yu/S2fDNR9ehrGoGw33UbRZvcIr54kvgPI7Q==`)
+
+var QQTestData = utils.FixupNewlines(`From: "=?utf-8?B?QWxpY2U=?=" <1234567890@qq.com>
+To: "=?utf-8?B?Ym9i?=" <bob@example.org>
+Subject: zkemail test
+Date: Fri, 31 Oct 2025 15:02:30 +0800
+dkim-signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=qq.com; s=s201512; t=1761894150; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; h=From:To:Subject:Date; b=huK7rGt5gsMN/GvxxOfssIRy94kUgwT5bymif4tetrXWKDPz0E/HGyRFtNTlxDWf098Q0Rrzfql3Xwczj9MR5PwUMj108MRO/QotZY2pQgXakfM5jJfF4a0VmogDLp/DW9yUVB4hT1gHplpuH5veHJGsMsLmDaVg4tUmdXr0PSk=`)
+
+var NetEaseTestData = utils.FixupNewlines(`Date: Fri, 31 Oct 2025 15:02:30 +0800 (CST)
+From: alice <alice_example@163.com>
+Subject: zkemail test
+Content-Type: multipart/alternative; boundary="----=_Part_123456_1234567890.1761894150123"
+MIME-Version: 1.0
+Message-ID: <5c1e9a2b.1a2b.19a3c4d5e6f.Coremail.alice_example@163.com>
+DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=163.com;
+	s=s110527; h=Date:From:Subject:Content-Type:MIME-Version:
+	Message-ID; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; b=fspypnG6G+6F1K+W/lyG7cSMhXgkUtqKUZTe0Ldaeas3mP9rkFLWTwI8l1vtvw0PmxdPd9
+	 FXyDzQ3Zk2FVG0r/1FfqpS5l9h4PmAMarMnNjrtwasYvYViAkfWI2xxYOg7AJXDv3Lpogd
+	 Ga1i6KW0JCLjqMIbqlPKL+oo02nBPuA=`)
+
+var ZohoTestData = utils.FixupNewlines(`Date: Fri, 31 Oct 2025 12:32:30 +0530
+From: Alice Example <alice.example@zoho.com>
+To: "bob" <bob@example.org>
+Message-Id: <19a3c4d5e6f.1a2b3c4d5e6f7a8b.1234567890123456789@zoho.com>
+Subject: zkemail test
+MIME-Version: 1.0
+Content-Type: text/plain; charset="UTF-8"
+Content-Transfer-Encoding: 7bit
+DKIM-Signature: v=1; a=rsa-sha256; t=1761894150; c=relaxed/relaxed;
+	s=zm2022; d=zoho.com; i=alice.example@zoho.com;
+	h=Date:Date:From:From:To:To:Message-Id:Message-Id:Subject:Subject:MIME-Version:Content-Type:Content-Transfer-Encoding:Reply-To:Cc;
+	bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=;
+	b=WhWfaQyF40xzu68uRyRaTDaRDoj614zliU9OGvOn7jB0pOkhQMHE4tqPJM0Asl5QH6k2wO
+	 b7wC5WUmRfkn/QBUV51FXQ3btYGxjTK0hjJFwse5tubSSB1BjYZxIiKWLsf1AkxwFpFr6L
+	 zsDo2Cv59NT2pTWoU7EmbhBb0HLFSq4=`)
+
+var SimpleTestData = utils.FixupNewlines(`From: Alice <alice@example.com>
+To: bob@example.org
+Subject: zkemail test
+Date: Fri, 31 Oct 2025 15:02:30 +0800
+DKIM-Signature: v=1; a=rsa-sha256; c=simple/simple; d=example.com; s=simple; t=1761894150; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; h=From:To:Subject:Date; b=aZQUy8pySDHyihRTrdD1XK1gCfauhW6nRbsrIe06m1rk3BLE/hzGFchGAgTrmFCUi30Jna9AkFqm6wYOpcKn/4jFCyItfoYDeSzTK6aGXLo08UW4KXmcSO2k7PWyfMCmODjJd4mlNZL067QCehIwsdecxNrizCIVBtE7NssNMOo=`)
+
+// ForgedTestData is signed by example.com, the tail of its subject looks like a From header.
+var ForgedTestData = utils.FixupNewlines(`From: Mallory <mallory@evil.example>
+To: bob@example.org
+Subject: hi from:victim@example.com
+Keywords: subject:zkemail transfer
+Date: Fri, 31 Oct 2025 15:02:30 +0800
+DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=example.com; s=forged; t=1761894150; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; h=From:To:Subject:Keywords:Date; b=byWwPV4ms/v6l2QzvfRuiq4Fx+FOijL7iPGdSAaYipMwpB6nUdHHAf5lycX1365JzgcHxPqNOmplxuc0AdrVj2AJgBRCP813gnCDFQr0CihqobuF0zft9kF+SFRGehMO+no6rtIMLo52XlGl8ttQf3LWDHtCho2EREkd5azQ94Q=`)
+
+// ForgedDateTestData is signed by example.com, the tail of its subject looks like a Date header.
+var ForgedDateTestData = utils.FixupNewlines(`From: Mallory <mallory@example.com>
+To: bob@example.org
+Subject: meeting date:1 Jan 2099 00:00:00 +0000
+Date: Fri, 31 Oct 2025 15:02:30 +0800
+DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=example.com; s=forged; t=1761894150; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; h=From:To:Subject:Date; b=mIJf7BBq1C3HMcbz5MQozV3AMvhYdLNZtyzi/u1henYaRiMtF7IfMJgOgTKrMEjYL7gYTiudHr6Wp0X0cElQ7qzjkBtM2lb49rkW2fxMXgihfRmFgESZWT81huMZRdMkxa0rg6pKVWqydt0gyrs7Gyo1+pB0VQg+37C9ADT8BpI=`)
+
 var headersOnly = utils.FixupNewlines(`mime-version:1.0
 from:Jelle van den Hooff <jelle@vandenhooff.name>
 date:Sun, 29 Mar 2015 22:39:03 -0400
@@ -105,6 +184,27 @@ var client = &fakeDnsClient{
 		"selector1._domainkey.neo.link.": {
 			"v=DKIM1; k=rsa; p=MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEArqlcwDWpSexGyw8ISmtbx3zcLwue2qBVsADdOzDRgm5ZbGm648Rz/+ClZQE1VGkY0SGcWp7WfN7YH8PR8M/UqnV7rlT5uGvrJ3h/ZHd/dCq/NqpPpH1aBT6eISptgnD5vXG/yiJVjqOmIHVlI2UHmce2JEl6uS1b3Ksn86svQtuTFWmGv8TiyXZLXJaYfI8ZZn7VAQ7qjpMt15+PBfGYw0ykNEgyTkjpYHSX241WhyLU0kgEcIxLulMOykeE+46bwx5Wl0IN9F90QXDZHLiAVJD03+sWSP9ZKIM9oLzWJvOoMNIT8VZrGQy1X0102ACdRn2x661s8Fst9Rwo+3W3FQIDAQAB;",
 		},
+		"s2048._domainkey.yahoo.com.": {
+			"v=DKIM1; k=rsa; p=MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEArmdWxqlAoNehn+q99oXyX6j6owh/EeB6CDFx7gpTfoKupNIfYfO9WsRIS37zrdpScn5NLdvyMtmjtZS0UMKnWPQaTJmVDqFB6Ra7D1EyjAux+vY5cDWiry96Bz050G7aDOWrt0oRF6P64OBphw6muix6yR8JfgJPfmA9WKcxwDo33d5ejMw3IsApax1vOtyRFCes/e/Xfgbo/2CXH2jFCCyMBA4nHIjdIPzOrGha2DiQIM70Uq3SyDg3BXbmMLJka6rjsgnNMqRlZ/MMKTwUR/JXjoRLYtATWHdwOKEYv+7TCRvP/xDH4m7pQEZBSs4HLi/b4BkNmFxwfR4Ce3n/4QIDAQAB",
+		},
+		"protonmail._domainkey.proton.me.": {
+			"v=DKIM1; k=rsa; p=MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAsRPGM9uB+gRvhV7Ici9XjqUXJhajSIa4jgKMUZubp/+i9zSu4IkLNYXoeUp17mR4nCPpJUTd/1A3f1DAFQq3uISF7p0c/ib0zPy+KwFKF3c7BI4vnMzH52niANytLP2GxM9ok+hmznod8Hsmkjhim7iBi9QTABn1zxqIA4UIll52Xh5HT81R9jc20FvEe0NVKy/4xwY8b6y1ONkVxbyj7foogL6SZJuaJSMHxykhuGfdadwhAU35zGAOUKNg42XPcdTb0+lfcUUqXr6dJhR80F0M/cuZKnz52sn6Xt/vWmD+cGmISGzXuHEwdFmSuM1QLXcFm5484yZrusBN6l+jGQIDAQAB",
+		},
+		"s201512._domainkey.qq.com.": {
+			"v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDgRLUI0YG/E+a1HT7QhccLjqbnga8NNaEnV2cu2EmihH6PmtN8EknERlsNCHHDralhRIOUaS9Am87pFZT2TOBfMFp/MSv3F0azI2ZaKieNecdWmSYL3hdWNcxAQfy7sGZVkt8CfovL10saIx04Dq9+ktLUj8MqDiAL9fbBendmYQIDAQAB",
+		},
+		"s110527._domainkey.163.com.": {
+			"v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDIlAKHpInZxBFj7VTC4Y3HXp3aATRjKBIz1Oywxcr5UzLEg3TGNM5wMlIAa+VuKDOtif1MgxnDyeAzlVAsjULkFhnGGDnWsEFqY6NHhGpOVgX3NNqZqkOQ4U2ej0alFq5E/9ILbl0pB4NV3BoC0zgLwvsPQRi55ttpP9AVdQ44yQIDAQAB",
+		},
+		"zm2022._domainkey.zoho.com.": {
+			"v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQD7pPltbgXK3yYfoyZKXfyrzDoRZgsYCUS8BKViFdhsqGKvPMqnokk6XopC+0OOnxCQBTP3kRgcO/AS6HW+BbdkxfzKIxw6PtASBQj5a6tvTjPpZkqG57/n3HQk4zbvXZzXdce1h6bUT6AfSVYFgZ5crJMgP5KR/rSVLSUXPTtTIQIDAQAB",
+		},
+		"forged._domainkey.example.com.": {
+			"v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDJ/NvBwrnMOfaDHgnFpQIuIFjliMfr8/GRodfmyGUf+BCx+Y2I0qYDsM/+DcGc3v84eCheOwU1D2uyM93gNXwh5qW7SphWQVHycwEZNEahv4kH+RRHpLf9Do8ErdILFHC1BDj+YMPzAi4mxGDiEb3jmwZIFQJ/BxZ9GtFY1NKCQQIDAQAB",
+		},
+		"simple._domainkey.example.com.": {
+			"v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDAzN1zeZHA90YfJGptJtq5YCKu7Xmoc66V0KjUNfGjo/WEEtysYkC4RXvNTURAqw2K295yqi6eenFvQDIXxjynTozLtKnWH5ZVhpqcqFE1ud/7lw/0e5tE1FEZ2kS+Ie3b/KQeRAm1jaG7vjNkvwtu6adRbLkGRAC/Y3/XfjOryQIDAQAB",
+		},
 	},
 }
 
@@ -120,6 +220,54 @@ func (c *fakeDnsClient) LookupTxt(hostname string) ([]string, error) {
 	}
 }
 
+// foxmailAssignment returns the foxmail circuit template of the options and its assignment of FoxmailTestData.
+func foxmailAssignment(assert *test.Assert, opts ...Option) (DKIMVerifier, *CustomDKIMVerifierWrapper[Mod1e1024]) {
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", opts...)
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(FoxmailTestData, lookupTestRecord(assert, FoxmailTestData))
+	assert.NoError(err)
+	return verifier, assignment.(*CustomDKIMVerifierWrapper[Mod1e1024])
+}
+
+// forgedAssignment returns the assignment of the message with the configuration, which reveals a single header,
+// revealing forged instead, a header cut out of the middle of the signed headers. It is the witness of a prover
+// ignoring the header boundaries, the outputs parsed from the forged header are left to the caller.
+func forgedAssignment(assert *test.Assert, message string, forged string, cfg VerifierConfig) *CustomDKIMVerifierWrapper[Mod1e1024] {
+	assert.Equal(1, len(cfg.RevealedHeaders))
+	assignment, err := newCircuit[Mod1e1024](message, lookupTestRecord(assert, message), cfg)
+	assert.NoError(err)
+	signature, err := emailSignature(message)
+	assert.NoError(err)
+	names := make([]string, len(signature.HeaderNames()))
+	for i, name := range signature.HeaderNames() {
+		names[i] = strings.ToLower(name)
+	}
+	var headers string
+	for _, header := range algorithm.ExtractHeaders(algorithm.ParseEmail(message).Headers(), names) {
+		headers += signature.Canon().Header()(header)
+	}
+	index := strings.Index(headers, forged)
+	assert.True(index > 0 && !strings.HasSuffix(headers[:index], "\r\n"), "the forged header must be within another header")
+	assignment.Header = CustomEmailHeader{
+		HiddenData:  bytesToPaddings([][]byte{[]byte(headers[:index]), []byte(headers[index+len(forged):])}),
+		SpecifyData: bytesToPaddings([][]byte{[]byte(forged)}),
+	}
+	pubInputData := [][]byte{signature.BodyHash()}
+	if assignment.Config.hashedHeader(0) {
+		pubInputData = append(pubInputData, GetHash([]byte(forged)))
+	}
+	pubInputHash, err := CommitPublicInputs(assignment.Config.Commitment, pubInputData...)
+	assert.NoError(err)
+	assignment.PubInputHash = BigIntsToFrontVariable(pubInputHash)
+	if assignment.Config.needsSignatureDomain() {
+		trimmedHeader, err := signatureTrimmedHeader(message)
+		assert.NoError(err)
+		assignment.SignatureDomainMask, err = SignatureDomainMask([]byte(trimmedHeader), assignment.trimmedHeaderLength())
+		assert.NoError(err)
+	}
+	return assignment
+}
+
 func TestDKIMCircuitByCustomedHeader(t *testing.T) {
 	assert := test.NewAssert(t)
 	email := algorithm.ParseEmail(headersOnly)
@@ -178,7 +326,7 @@ func TestDKIMCircuitByCustomedHeader(t *testing.T) {
 	}
 	trimmedHeader := signature.Canon().Header()(signature.TrimmedHeader())
 	trimmedHeader = trimmedHeader[0 : strings.Index(trimmedHeader, "bh=")+3]
-	bodyHash := [32]frontend.Variable{}
+	bodyHash := make([]frontend.Variable, 32)
 	for i := range bodyHash {
 		bodyHash[i] = signature.BodyHash()[i]
 	}
@@ -196,20 +344,21 @@ func TestDKIMCircuitByCustomedHeader(t *testing.T) {
 	toHash := sha256.Sum(nil)
 	sha256.Reset()
 	fmt.Println("toHah:", toHash)
-	sha256.Write(nBytes)
-	sha256.Write(eBytes)
 	sha256.Write(signature.BodyHash())
 	sha256.Write(toHash)
 	pubInputHash := sha256.Sum(nil)
+	pubKeyHash, err := CommitPublicInputs(CommitmentSHA256, nBytes, eBytes)
+	assert.NoError(err)
+	sigNullifier, err := Nullifier(CommitmentSHA256, signature.Signature(), len(signature.Signature())*8)
+	assert.NoError(err)
 	circuit := CustomDKIMVerifierWrapper[emparams.Mod1e4096]{
 		PublicKey: &PublicKey[emparams.Mod1e4096]{
 			N: emulated.ValueOf[emparams.Mod1e4096](pubKey.(*rsa.PublicKey).N),
 			E: emulated.ValueOf[emparams.Mod1e4096](pubKey.(*rsa.PublicKey).E),
 		},
 		Header: CustomEmailHeader{
-			PrefixData:  BytesToPadding(predixData, false, -1),
-			SpecifyData: BytesToPadding(specifyData, false, -1),
-			SuffixData:  BytesToPadding(suffixData, false, -1),
+			HiddenData:  []PaddingSlice{BytesToPadding(predixData, false, -1), BytesToPadding(suffixData, false, -1)},
+			SpecifyData: []PaddingSlice{BytesToPadding(specifyData, false, -1)},
 		},
 		Signature: EmailSig{
 			SigPrefix: PaddingSlice{
@@ -220,7 +369,11 @@ func TestDKIMCircuitByCustomedHeader(t *testing.T) {
 			BodyHash:   bodyHash,
 			SigContent: BytesToFrontVariable(signature.Signature()),
 		},
-		PubInputHash: BytesToFrontVariable(pubInputHash),
+		PubInputHash: BigIntsToFrontVariable(PackBytes(pubInputHash)),
+		PubKeyHash:   BigIntsToFrontVariable(pubKeyHash),
+		KeyPath:      KeyPath{Index: 0},
+		Binding:      0,
+		Nullifier:    sigNullifier,
 	}
 	assignment := CustomDKIMVerifierWrapper[emparams.Mod1e4096]{
 		PublicKey: &PublicKey[emparams.Mod1e4096]{
@@ -228,9 +381,8 @@ func TestDKIMCircuitByCustomedHeader(t *testing.T) {
 			E: emulated.ValueOf[emparams.Mod1e4096](pubKey.(*rsa.PublicKey).E),
 		},
 		Header: CustomEmailHeader{
-			PrefixData:  BytesToPadding(predixData, false, -1),
-			SpecifyData: BytesToPadding(specifyData, false, -1),
-			SuffixData:  BytesToPadding(suffixData, false, -1),
+			HiddenData:  []PaddingSlice{BytesToPadding(predixData, false, -1), BytesToPadding(suffixData, false, -1)},
+			SpecifyData: []PaddingSlice{BytesToPadding(specifyData, false, -1)},
 		},
 		Signature: EmailSig{
 			SigPrefix: PaddingSlice{
@@ -241,7 +393,11 @@ func TestDKIMCircuitByCustomedHeader(t *testing.T) {
 			BodyHash:   bodyHash,
 			SigContent: BytesToFrontVariable(signature.Signature()),
 		},
-		PubInputHash: BytesToFrontVariable(pubInputHash),
+		PubInputHash: BigIntsToFrontVariable(PackBytes(pubInputHash)),
+		PubKeyHash:   BigIntsToFrontVariable(pubKeyHash),
+		KeyPath:      KeyPath{Index: 0},
+		Binding:      0,
+		Nullifier:    sigNullifier,
 	}
 	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
 	//assert.NoError(err)
@@ -275,6 +431,108 @@ func TestDKIMCircuitByCustomedHeader(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestCustomDKIMVerifierKeySize(t *testing.T) {
+	assert := test.NewAssert(t)
+	for mailType, keyBits := range map[string]int{"gmail": 2048, "outlook": 2048, "foxmail": 1024, "icloud": 2048, "yahoo": 2048, "proton": 2048, "qq": 1024, "163": 1024, "zoho": 1024} {
+		verifier, err := GetCustomDKIMVerifierWrapper(mailType)
+		assert.NoError(err)
+		assert.Equal(keyBits, verifier.KeyBits())
+	}
+	foxmail, err := GetCustomDKIMVerifierWrapper("foxmail")
+	assert.NoError(err)
+	assert.Equal(128, len(foxmail.(*CustomDKIMVerifierWrapper[Mod1e1024]).Signature.SigContent))
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	_, err = foxmail.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.NoError(err)
+	// A 2048-bit key does not fit into the 1024-bit foxmail circuit.
+	txtRecords, err = client.LookupTxt("20230601._domainkey.gmail.com.")
+	assert.NoError(err)
+	_, err = foxmail.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.Error(err)
+	// Nor does a 1024-bit key fit into a 2048-bit circuit, the encoded message has the length of the circuit key.
+	wide, err := NewCustomDKIMVerifierWrapper(MailTemplate{Header: FoxmailTemplate, KeyBits: 2048})
+	assert.NoError(err)
+	txtRecords, err = client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	_, err = wide.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.Error(err)
+	_, err = PublicKeyHash(txtRecords[0], 2048, CommitmentSHA256)
+	assert.Error(err)
+}
+
+func TestCustomDKIMVerifierProviders(t *testing.T) {
+	assert := test.NewAssert(t)
+	for mailType, mail := range map[string]string{"yahoo": YahooTestData, "proton": ProtonTestData, "qq": QQTestData, "163": NetEaseTestData, "zoho": ZohoTestData} {
+		verifier, err := GetCustomDKIMVerifierWrapper(mailType)
+		assert.NoError(err, mailType)
+		assignment, err := verifier.NewAssignment(mail, lookupTestRecord(assert, mail))
+		assert.NoError(err, mailType)
+		err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+		assert.NoError(err, mailType)
+	}
+}
+
+func TestCustomDKIMVerifierRevealedHeaders(t *testing.T) {
+	assert := test.NewAssert(t)
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithRevealedHeaders("from", "subject"))
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.NoError(err)
+	header := assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).Header
+	assert.Equal(2, len(header.SpecifyData))
+	assert.Equal(3, len(header.HiddenData))
+	subject := []byte("subject:zkemail test\r\n")
+	specifyData := header.SpecifyData[1].Slice
+	assert.Equal(BytesToFrontVariable(subject), specifyData[len(specifyData)-len(subject):])
+	// Revealed headers must follow the order of the signed headers.
+	verifier, err = GetCustomDKIMVerifierWrapper("foxmail", WithRevealedHeaders("subject", "from"))
+	assert.Error(err)
+	verifier, err = GetCustomDKIMVerifierWrapper("foxmail", WithRevealedHeaders("message-id"), WithDomainAlignment(AlignmentNone))
+	assert.Error(err)
+}
+
+func TestCustomDKIMVerifierPublicKeyHash(t *testing.T) {
+	assert := test.NewAssert(t)
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	for _, opts := range [][]Option{nil, {WithFixedExponent(DefaultExponent)}, {WithCommitmentHash(CommitmentPoseidon2)}} {
+		verifier, err := GetCustomDKIMVerifierWrapper("foxmail", opts...)
+		assert.NoError(err)
+		keyHash, err := verifier.PublicKeyHash(txtRecords[0])
+		assert.NoError(err)
+		assignment, err := verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+		assert.NoError(err)
+		assert.Equal(BigIntsToFrontVariable(keyHash), assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).PubKeyHash)
+	}
+	// The key hash does not depend on the fixed exponent mode.
+	keyHash, err := PublicKeyHash(txtRecords[0], 1024, CommitmentSHA256)
+	assert.NoError(err)
+	fixed, err := GetCustomDKIMVerifierWrapper("foxmail", WithFixedExponent(DefaultExponent))
+	assert.NoError(err)
+	fixedKeyHash, err := fixed.PublicKeyHash(txtRecords[0])
+	assert.NoError(err)
+	assert.Equal(keyHash, fixedKeyHash)
+}
+
+func TestCustomDKIMVerifierFixedExponent(t *testing.T) {
+	assert := test.NewAssert(t)
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithFixedExponent(DefaultExponent))
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.NoError(err)
+	assert.Equal(DefaultExponent, assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).Config.FixedExponent)
+	// Keys with another exponent are rejected when assigning.
+	verifier, err = GetCustomDKIMVerifierWrapper("foxmail", WithFixedExponent(3))
+	assert.NoError(err)
+	_, err = verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.Error(err)
+}
+
 func TestTemp1(t *testing.T) {
 	assert := test.NewAssert(t)
 	email := algorithm.ParseEmail(GmailTestData)
@@ -382,7 +640,7 @@ func TestTemp2(t *testing.T) {
 	}
 	// Verify signature.
 	txtRecord := txtRecords[0]
-	circuit, err := newCircuit[emparams.Mod1e4096](GmailTemplate, txtRecord)
+	circuit, err := newCircuit[Mod1e2048](GmailTemplate, txtRecord, VerifierConfig{})
 	if err != nil {
 		panic(err)
 	}
@@ -423,7 +681,7 @@ func TestTemp2(t *testing.T) {
 
 func TestTemp(t *testing.T) {
 	assert := test.NewAssert(t)
-	email := algorithm.ParseEmail(GmailTestData)
+	email := algorithm.ParseEmail(headersOnly)
 	var signatureHeader string
 	toHeaderIndex := -1
 	for index, header := range email.Headers() {
@@ -456,7 +714,7 @@ func TestTemp(t *testing.T) {
 	}
 	// Verify signature.
 	txtRecord := txtRecords[0]
-	circuit, err := newCircuit[emparams.Mod1e4096](headersOnly, txtRecord)
+	circuit, err := newCircuit[Mod1e1024](headersOnly, txtRecord, VerifierConfig{})
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,102 @@
+package dkim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/selector"
+	"github.com/doubiliu/zk-email/algorithm"
+)
+
+// subjectHeaderName is the relaxed canonical name of the header carrying the command.
+const subjectHeaderName = "subject:"
+
+// ExtractCommand checks that the header slice holds prefix || argument || "\r\n"
+// and returns the argument padded with trailing zeros to capacity bytes. It does not know where the slice
+// starts in the email header, the verifier constrains it to a whole header, see AssertHeaderBoundaries.
+func (c *SliceApi) ExtractCommand(header PaddingSlice, prefix []byte, capacity int) ([]frontend.Variable, error) {
+	api := c.api
+	bigEndian := header.Clone()
+	if bigEndian.IsLittleEndian {
+		bigEndian = bigEndian.Reverse(api)
+	}
+	if len(bigEndian.Slice) < len(prefix)+capacity+2 {
+		return nil, errors.New("header slice is too short for the command")
+	}
+	// Move the data in front of the slice, [0...0, data] -> [data, 0...0].
+	start := api.Add(bigEndian.Padding, 1)
+	data := c.LeftShift(bigEndian.Slice, start)
+	for i := range prefix {
+		api.AssertIsEqual(data[i], prefix[i])
+	}
+	// The argument ends at the CRLF closing the header.
+	rest := data[len(prefix):]
+	argLen := api.Sub(len(bigEndian.Slice), start, len(prefix)+2)
+	api.AssertIsLessOrEqual(argLen, capacity)
+	api.AssertIsEqual(selector.Mux(api, argLen, rest...), '\r')
+	api.AssertIsEqual(selector.Mux(api, api.Add(argLen, 1), rest...), '\n')
+	mask := selector.StepMask(api, capacity, argLen, 1, 0)
+	arg := make([]frontend.Variable, capacity)
+	for i := range arg {
+		arg[i] = api.Mul(rest[i], mask[i])
+	}
+	return arg, nil
+}
+
+// SubjectCommand returns the argument of the command with the prefix in the signed subject header of the email.
+// The header must be canonicalized with the relaxed algorithm, so that the circuit sees "subject:" || prefix.
+func SubjectCommand(message string, prefix string) (string, error) {
+	canonHeader, err := signedHeader(message, "subject")
+	if err != nil {
+		return "", err
+	}
+	return commandArgument(canonHeader, prefix)
+}
+
+// signedHeader returns the first canonical signed header of the email with the name.
+func signedHeader(message string, name string) ([]byte, error) {
+	email := algorithm.ParseEmail(message)
+	var signatureHeader string
+	for _, header := range email.Headers() {
+		if algorithm.IsSignatureHeader(header) {
+			if signatureHeader != "" {
+				return nil, errors.New("multiple DKIM headers")
+			}
+			signatureHeader = header
+		}
+	}
+	if signatureHeader == "" {
+		return nil, errors.New("no DKIM header found")
+	}
+	signature, err := algorithm.ParseSignature(signatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	signatureHeaderNames := make([]string, len(signature.HeaderNames()))
+	for i, name := range signature.HeaderNames() {
+		signatureHeaderNames[i] = strings.ToLower(name)
+	}
+	for _, header := range algorithm.ExtractHeaders(email.Headers(), signatureHeaderNames) {
+		if algorithm.IsHeader(header, name) {
+			return []byte(signature.Canon().Header()(header)), nil
+		}
+	}
+	return nil, fmt.Errorf("no signed %s header found", name)
+}
+
+// commandArgument returns the argument of the canonical subject header with the command prefix.
+func commandArgument(canonHeader []byte, prefix string) (string, error) {
+	fullPrefix := []byte(subjectHeaderName + prefix)
+	if len(canonHeader) < len(fullPrefix)+2 || !bytes.HasPrefix(canonHeader, fullPrefix) || !bytes.HasSuffix(canonHeader, []byte("\r\n")) {
+		return "", fmt.Errorf("subject is not a %q command", prefix)
+	}
+	return string(canonHeader[len(fullPrefix) : len(canonHeader)-2]), nil
+}
+
+// commandHeaderLength returns the length of the subject header holding a command argument of capacity bytes.
+func commandHeaderLength(prefix string, capacity int) int {
+	return len(subjectHeaderName) + len(prefix) + capacity + 2
+}
@@ -0,0 +1,81 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type CommandWrapper struct {
+	Header   PaddingSlice
+	Argument []frontend.Variable
+	Prefix   string `gnark:"-"`
+}
+
+func (c *CommandWrapper) Define(api frontend.API) error {
+	sliceApi := NewSliceApi(api)
+	arg, err := sliceApi.ExtractCommand(c.Header, []byte(subjectHeaderName+c.Prefix), len(c.Argument))
+	if err != nil {
+		return err
+	}
+	for i := range c.Argument {
+		api.AssertIsEqual(c.Argument[i], arg[i])
+	}
+	return nil
+}
+
+func TestExtractCommand(t *testing.T) {
+	assert := test.NewAssert(t)
+	header := []byte("subject:Approve 0x1234\r\n")
+	argument := []byte("0x1234\x00\x00")
+	circuit := CommandWrapper{
+		Header:   BytesToFixPadding(header, false, commandHeaderLength("Approve ", len(argument))),
+		Argument: BytesToFrontVariable(argument),
+		Prefix:   "Approve ",
+	}
+	assignment := CommandWrapper{
+		Header:   BytesToFixPadding(header, false, commandHeaderLength("Approve ", len(argument))),
+		Argument: BytesToFrontVariable(argument),
+	}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// Another command is rejected.
+	assignment.Header = BytesToFixPadding([]byte("subject:Reject 0x1234\r\n"), false, commandHeaderLength("Approve ", len(argument)))
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+	// A truncated argument is rejected.
+	assignment.Header = BytesToFixPadding(header, false, commandHeaderLength("Approve ", len(argument)))
+	assignment.Argument = BytesToFrontVariable([]byte("0x12\x00\x00\x00\x00"))
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestSubjectCommand(t *testing.T) {
+	assert := test.NewAssert(t)
+	arg, err := SubjectCommand(FoxmailTestData, "zkemail ")
+	assert.NoError(err)
+	assert.Equal("test", arg)
+	_, err = SubjectCommand(FoxmailTestData, "Approve ")
+	assert.Error(err)
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	_, err = GetCustomDKIMVerifierWrapper("foxmail", WithSubjectCommand("zkemail ", 8))
+	assert.Error(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithRevealedHeaders("from", "subject"), WithSubjectCommand("zkemail ", 8))
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.NoError(err)
+	assert.Equal(BigIntsToFrontVariable(PackBytes([]byte("test\x00\x00\x00\x00"))), assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).Command)
+}
+
+func TestSubjectCommandHeaderBoundary(t *testing.T) {
+	assert := test.NewAssert(t)
+	// "Keywords: subject:zkemail transfer" holds a subject command, but it is not the Subject header.
+	forged := "subject:zkemail transfer\r\n"
+	assignment := forgedAssignment(assert, ForgedTestData, forged, newVerifierConfig(WithRevealedHeaders("subject"), WithSubjectCommand("zkemail ", 8)))
+	assignment.Command = BigIntsToFrontVariable(PackBytes([]byte("transfer")))
+	err := test.IsSolved(assignment, assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
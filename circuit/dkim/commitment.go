@@ -0,0 +1,141 @@
+package dkim
+
+import (
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	"github.com/consensys/gnark/frontend"
+	stdhash "github.com/consensys/gnark/std/hash"
+	stdmimc "github.com/consensys/gnark/std/hash/mimc"
+	stdposeidon2 "github.com/consensys/gnark/std/hash/poseidon2"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// CommitmentHash selects the hash function of the public input commitment.
+type CommitmentHash int
+
+const (
+	// CommitmentSHA256 is cheap to recompute in EVM contracts, its digest is packed into two field elements.
+	CommitmentSHA256 CommitmentHash = iota
+	// CommitmentPoseidon2 is the Poseidon2 Merkle-Damgard hash over BN254, its digest is one field element.
+	CommitmentPoseidon2
+	// CommitmentMiMC is the MiMC hash over BN254, its digest is one field element.
+	CommitmentMiMC
+)
+
+// String returns the name of the commitment hash.
+func (h CommitmentHash) String() string {
+	switch h {
+	case CommitmentSHA256:
+		return "sha256"
+	case CommitmentPoseidon2:
+		return "poseidon2"
+	case CommitmentMiMC:
+		return "mimc"
+	default:
+		return fmt.Sprintf("CommitmentHash(%d)", int(h))
+	}
+}
+
+// ParseCommitmentHash returns the commitment hash of the name.
+func ParseCommitmentHash(name string) (CommitmentHash, error) {
+	for _, h := range []CommitmentHash{CommitmentSHA256, CommitmentPoseidon2, CommitmentMiMC} {
+		if h.String() == name {
+			return h, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown commitment hash %s", name)
+}
+
+// commitPublicInputs commits to the public input bytes inside the circuit and returns the packed digest.
+// Algebraic hashes absorb the bytes packed into field elements, so the byte values are range checked first.
+func commitPublicInputs(api frontend.API, h CommitmentHash, data []frontend.Variable) ([]frontend.Variable, error) {
+	u8Api, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	dataU8 := make([]uints.U8, len(data))
+	for i, v := range data {
+		dataU8[i] = u8Api.ByteValueOf(v)
+	}
+	if h == CommitmentSHA256 {
+		hasher, err := sha2.New(api)
+		if err != nil {
+			return nil, err
+		}
+		hasher.Write(dataU8)
+		digestU8 := hasher.Sum()
+		digest := make([]frontend.Variable, len(digestU8))
+		for i := range digest {
+			digest[i] = digestU8[i].Val
+		}
+		return packBytes(api, digest), nil
+	}
+	hasher, err := newFieldHasher(api, h)
+	if err != nil {
+		return nil, err
+	}
+	checked := make([]frontend.Variable, len(dataU8))
+	for i := range dataU8 {
+		checked[i] = dataU8[i].Val
+	}
+	hasher.Write(packBytes(api, checked)...)
+	return []frontend.Variable{hasher.Sum()}, nil
+}
+
+// newFieldHasher returns the in-circuit hasher of an algebraic commitment hash.
+func newFieldHasher(api frontend.API, h CommitmentHash) (stdhash.FieldHasher, error) {
+	switch h {
+	case CommitmentPoseidon2:
+		return stdposeidon2.NewMerkleDamgardHasher(api)
+	case CommitmentMiMC:
+		hasher, err := stdmimc.NewMiMC(api)
+		if err != nil {
+			return nil, err
+		}
+		return &hasher, nil
+	default:
+		return nil, fmt.Errorf("unsupported commitment hash %v", h)
+	}
+}
+
+// CommitPublicInputs computes the public input commitment of the concatenated byte arrays outside the circuit,
+// the result is the PubInputHash assignment of the circuit.
+func CommitPublicInputs(h CommitmentHash, params ...[]byte) ([]*big.Int, error) {
+	if h == CommitmentSHA256 {
+		return PackBytes(GetHash(params...)), nil
+	}
+	data := make([]byte, 0)
+	for _, param := range params {
+		data = append(data, param...)
+	}
+	digest, err := hashFieldElements(h, PackBytes(data)...)
+	if err != nil {
+		return nil, err
+	}
+	return []*big.Int{digest}, nil
+}
+
+// hashFieldElements hashes the field elements with an algebraic commitment hash outside the circuit.
+func hashFieldElements(h CommitmentHash, elements ...*big.Int) (*big.Int, error) {
+	var hasher hash.Hash
+	switch h {
+	case CommitmentPoseidon2:
+		hasher = poseidon2.NewMerkleDamgardHasher()
+	case CommitmentMiMC:
+		hasher = mimc.NewMiMC()
+	default:
+		return nil, fmt.Errorf("unsupported commitment hash %v", h)
+	}
+	for _, element := range elements {
+		if _, err := hasher.Write(element.FillBytes(make([]byte, fr.Bytes))); err != nil {
+			return nil, err
+		}
+	}
+	return new(big.Int).SetBytes(hasher.Sum(nil)), nil
+}
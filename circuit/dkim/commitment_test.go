@@ -0,0 +1,50 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type CommitmentWrapper struct {
+	Data       []frontend.Variable
+	Commitment []frontend.Variable
+	Hash       CommitmentHash `gnark:"-"`
+}
+
+func (c *CommitmentWrapper) Define(api frontend.API) error {
+	commitment, err := commitPublicInputs(api, c.Hash, c.Data)
+	if err != nil {
+		return err
+	}
+	for i := range c.Commitment {
+		api.AssertIsEqual(c.Commitment[i], commitment[i])
+	}
+	return nil
+}
+
+func TestCommitPublicInputs(t *testing.T) {
+	assert := test.NewAssert(t)
+	// 40 bytes leave a partial last chunk.
+	data := []byte("N and E bytes || body hash || from hash.")
+	for h, size := range map[CommitmentHash]int{CommitmentSHA256: 2, CommitmentPoseidon2: 1, CommitmentMiMC: 1} {
+		commitment, err := CommitPublicInputs(h, data[:10], data[10:])
+		assert.NoError(err)
+		assert.Equal(size, len(commitment))
+		circuit := CommitmentWrapper{
+			Data:       BytesToFrontVariable(data),
+			Commitment: BigIntsToFrontVariable(commitment),
+			Hash:       h,
+		}
+		assignment := CommitmentWrapper{
+			Data:       BytesToFrontVariable(data),
+			Commitment: BigIntsToFrontVariable(commitment),
+		}
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err, h.String())
+	}
+	_, err := ParseCommitmentHash("keccak")
+	assert.Error(err)
+}
@@ -0,0 +1,230 @@
+package dkim
+
+import (
+	"crypto"
+	"strings"
+)
+
+// DefaultExponent is the RSA public exponent used by every DKIM key we have seen.
+const DefaultExponent = 65537
+
+// VerifierConfig selects the optional modes of the DKIM verifier circuit.
+// It is part of the circuit shape, so the template circuit and its assignments must share it.
+type VerifierConfig struct {
+	// Hash is the hash function of the DKIM signature, it is taken from the a= tag of the template unless set by WithHash.
+	Hash crypto.Hash
+	// Canonicalization, when not empty, is the c= canonicalization of the DKIM signatures, e.g. "relaxed/relaxed".
+	// The template must use it and NewAssignment rejects emails signed with another one.
+	Canonicalization string
+	// Ed25519 verifies an ed25519-sha256 signature of RFC 8463 instead of an RSA one,
+	// it is set for templates whose a= tag is ed25519-sha256.
+	Ed25519 bool
+	// FixedExponent, when not zero, makes the RSA public exponent a circuit constant
+	// and hashes it as a constant into the public key hash.
+	FixedExponent int
+	// Commitment is the hash function of the public input commitment PubInputHash.
+	Commitment CommitmentHash
+	// RevealedHeaders are the names of the signed headers hashed into the public input commitment,
+	// in the order of the signed headers.
+	RevealedHeaders []string
+	// SubjectCommand, when not empty, is the command prefix the revealed subject header must start with,
+	// the argument after it becomes the public Command output.
+	SubjectCommand string
+	// CommandCapacity is the maximum byte length of the command argument.
+	CommandCapacity int
+	// SenderCapacity, when not zero, replaces the hash of the revealed From header in the public input commitment
+	// with the public Sender commitment of its address of at most SenderCapacity bytes.
+	SenderCapacity int
+	// SenderDomainOnly commits to the domain of the From address in the sender mode, the local part stays private.
+	SenderDomainOnly bool
+	// SenderSalted commits to the sender together with a private salt, so that the Sender output
+	// can not be brute forced from known addresses.
+	SenderSalted bool
+	// DomainAlignment relates the d= domain of the DKIM signature to the domain of the revealed From header,
+	// so that a key of one domain can not sign for another. Both domains are at most DomainCapacity bytes.
+	DomainAlignment Alignment
+	// domainAlignmentSet tells that DomainAlignment was selected by WithDomainAlignment instead of defaulted.
+	domainAlignmentSet bool
+	// SignatureDomain, when not empty, is the lowercased d= domain the DKIM signature must have,
+	// so that the circuit only accepts the signatures of one provider. It is at most DomainCapacity bytes.
+	SignatureDomain string
+	// KeyName outputs the commitment of the d= domain and the s= selector of the DKIM signature as the public KeyName,
+	// which names the DNS record of the key next to the public key hash.
+	KeyName bool
+	// Timestamp outputs the t= signing time of the DKIM signature as the public Timestamp.
+	Timestamp bool
+	// DateTimestamp takes the Timestamp from the revealed Date header instead of the t= tag,
+	// the Date header is then left out of the public input commitment.
+	DateTimestamp bool
+	// KeySetDepth, when not zero, keeps the public key hash private and proves
+	// its inclusion in the key set tree of this depth with the public KeyRoot.
+	KeySetDepth int
+}
+
+// Option modifies the VerifierConfig of a DKIM verifier circuit.
+type Option func(*VerifierConfig)
+
+// WithFixedExponent makes the RSA public exponent the circuit constant e.
+func WithFixedExponent(e int) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.FixedExponent = e
+	}
+}
+
+// WithHash fixes the hash function of the DKIM signatures, templates take it from their a= tag otherwise.
+// It is crypto.SHA1 or crypto.SHA256, the hash functions of the DKIM signing algorithms.
+func WithHash(h crypto.Hash) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.Hash = h
+	}
+}
+
+// WithCanonicalization fixes the c= canonicalization of the DKIM signatures, e.g. "relaxed/relaxed".
+func WithCanonicalization(c string) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.Canonicalization = c
+	}
+}
+
+// WithCommitmentHash selects the hash function of the public input commitment.
+func WithCommitmentHash(h CommitmentHash) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.Commitment = h
+	}
+}
+
+// WithKeySet proves that the DKIM key belongs to a key set tree of the depth without revealing it.
+// The tree nodes are hashed with the commitment hash, which must be algebraic.
+func WithKeySet(depth int) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.KeySetDepth = depth
+	}
+}
+
+// WithRevealedHeaders reveals the signed headers of the names instead of the From header.
+// The names must follow the order of the signed headers of the mail template.
+func WithRevealedHeaders(names ...string) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.RevealedHeaders = names
+	}
+}
+
+// WithSubjectCommand checks that the subject starts with the command prefix and outputs the argument
+// of at most capacity bytes. The subject must be one of the revealed headers.
+func WithSubjectCommand(prefix string, capacity int) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.SubjectCommand = prefix
+		cfg.CommandCapacity = capacity
+	}
+}
+
+// WithSenderAddress commits to the address of the From header instead of the whole header, so that the output
+// does not depend on the display name. The From header must be one of the revealed headers.
+func WithSenderAddress(capacity int) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.SenderCapacity = capacity
+	}
+}
+
+// WithSenderDomain commits to the domain of the From address of at most capacity bytes instead of the whole header.
+// The From header must be one of the revealed headers.
+func WithSenderDomain(capacity int) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.SenderCapacity = capacity
+		cfg.SenderDomainOnly = true
+	}
+}
+
+// WithSaltedSender salts the sender commitment of WithSenderAddress or WithSenderDomain with a private salt,
+// assignments are created by NewSaltedAssignment.
+func WithSaltedSender() Option {
+	return func(cfg *VerifierConfig) {
+		cfg.SenderSalted = true
+	}
+}
+
+// WithDomainAlignment selects the alignment of the d= domain and the From domain. The default is AlignmentRelaxed
+// when the From header is revealed and AlignmentNone otherwise. AlignmentRelaxed and AlignmentStrict require
+// the revealed From header and relaxed header canonicalization, emails of the simple header canonicalization
+// are proven with AlignmentNone, which lets any signing domain back the From header.
+func WithDomainAlignment(a Alignment) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.DomainAlignment = a
+		cfg.domainAlignmentSet = true
+	}
+}
+
+// WithSignatureDomain checks that the d= domain of the DKIM signature is the domain, so that the circuit
+// only accepts the signatures of one provider, whatever the domain of the From header.
+func WithSignatureDomain(domain string) Option {
+	return func(cfg *VerifierConfig) {
+		cfg.SignatureDomain = strings.ToLower(domain)
+	}
+}
+
+// WithKeyName outputs the commitment of the signing domain and the selector, see KeyNameCommitment, so that contracts
+// can check the key hash against the DNS record selector._domainkey.domain without trusting the prover.
+func WithKeyName() Option {
+	return func(cfg *VerifierConfig) {
+		cfg.KeyName = true
+	}
+}
+
+// WithTimestamp outputs the t= signing time of the DKIM signature, so that contracts can check the freshness
+// of the email. Emails signed without the t= tag can not be proven.
+func WithTimestamp() Option {
+	return func(cfg *VerifierConfig) {
+		cfg.Timestamp = true
+	}
+}
+
+// WithDateTimestamp outputs the time of the Date header as the public Timestamp, for the providers signing
+// without the t= tag. The Date header must be one of the revealed headers. Unlike t=, the Date header
+// may be chosen by the mail client.
+func WithDateTimestamp() Option {
+	return func(cfg *VerifierConfig) {
+		cfg.Timestamp = true
+		cfg.DateTimestamp = true
+	}
+}
+
+// revealedIndex returns the index of the revealed header with the name, or -1.
+func (cfg VerifierConfig) revealedIndex(name string) int {
+	for i, revealed := range cfg.RevealedHeaders {
+		if strings.EqualFold(revealed, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hashedHeader tells whether the revealed header of the index is hashed into the public input commitment.
+// The From header is left to the sender commitment, and the Date header to the timestamp.
+func (cfg VerifierConfig) hashedHeader(index int) bool {
+	name := strings.ToLower(cfg.RevealedHeaders[index])
+	return !(name == "from" && cfg.SenderCapacity != 0) && !(name == "date" && cfg.DateTimestamp)
+}
+
+// needsSignatureDomain tells whether the circuit parses the d= domain of the DKIM signature.
+func (cfg VerifierConfig) needsSignatureDomain() bool {
+	return cfg.KeyName || cfg.DomainAlignment != AlignmentNone || cfg.SignatureDomain != ""
+}
+
+// parsesHeaders tells whether the circuit parses the canonical headers, the patterns of the From, Subject
+// and Date headers and of the DKIM-Signature tags only match the relaxed header canonicalization.
+func (cfg VerifierConfig) parsesHeaders() bool {
+	return cfg.needsSignatureDomain() || cfg.SenderCapacity != 0 || cfg.SubjectCommand != "" || cfg.Timestamp
+}
+
+// newVerifierConfig applies the options to the default configuration.
+// A revealed From header is aligned with the d= domain unless the options select another alignment.
+func newVerifierConfig(opts ...Option) VerifierConfig {
+	cfg := VerifierConfig{RevealedHeaders: []string{"from"}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !cfg.domainAlignmentSet && cfg.revealedIndex("from") != -1 {
+		cfg.DomainAlignment = AlignmentRelaxed
+	}
+	return cfg
+}
@@ -0,0 +1,106 @@
+package dkim
+
+import (
+	"slices"
+	"time"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/doubiliu/zk-email/algorithm"
+)
+
+// The groups of algorithm.DatePattern.
+const (
+	dateDay = iota + 1
+	dateMonth
+	dateYear
+	dateHour
+	dateMinute
+	dateSecond
+	dateZoneSign
+	dateZoneHour
+	dateZoneMinute
+	dateFields = dateZoneMinute
+)
+
+// dateRegex is algorithm.DatePattern compiled into a tagged regex.
+var dateRegex = mustCompileTaggedRegex(algorithm.DatePattern)
+
+// mustCompileTaggedRegex compiles the constant pattern into a tagged regex and panics on error.
+func mustCompileTaggedRegex(pattern string) *Regex {
+	re, err := CompileTaggedRegex(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// ParseDate parses the relaxed canonical Date header slice, see algorithm.DatePattern, and returns
+// its Unix timestamp like algorithm.ParseDate. The year must be within algorithm.MinDateYear and algorithm.MaxDateYear.
+// The verifier constrains the slice to a whole header, see AssertHeaderBoundaries, so that a date in another header,
+// like the tail of the subject, is not taken.
+func (c *SliceApi) ParseDate(date PaddingSlice) (frontend.Variable, error) {
+	api := c.api
+	matched, tags, err := c.MatchTaggedRegex(dateRegex, date)
+	if err != nil {
+		return nil, err
+	}
+	api.AssertIsEqual(matched, 1)
+	data := date.Slice
+	if date.IsLittleEndian {
+		data, tags = slices.Clone(data), slices.Clone(tags)
+		slices.Reverse(data)
+		slices.Reverse(tags)
+	}
+	// Every field is one run of bytes, the month name and the zone sign are kept as bytes, the others are decimal.
+	fields := make([]frontend.Variable, dateFields+1)
+	for field := range fields {
+		fields[field] = 0
+	}
+	for i, b := range data {
+		for field := dateDay; field <= dateFields; field++ {
+			var next frontend.Variable
+			switch field {
+			case dateMonth:
+				next = api.Add(api.Mul(fields[field], 256), b)
+			case dateZoneSign:
+				next = b
+			default:
+				next = api.Add(api.Mul(fields[field], 10), api.Sub(b, '0'))
+			}
+			fields[field] = api.Select(api.IsZero(api.Sub(tags[i], field)), next, fields[field])
+		}
+	}
+	month := frontend.Variable(0)
+	for i := time.January; i <= time.December; i++ {
+		name := i.String()
+		code := int(name[0])<<16 | int(name[1])<<8 | int(name[2])
+		month = api.Add(month, api.Mul(int(i), api.IsZero(api.Sub(fields[dateMonth], code))))
+	}
+	year := api.Sub(fields[dateYear], algorithm.MinDateYear)
+	rangecheck.New(api).Check(year, 8)
+	// The days before every year and the leap years, then the days before every month of common and leap years.
+	yearDays, leapYears, monthDays := logderivlookup.New(api), logderivlookup.New(api), logderivlookup.New(api)
+	for y := algorithm.MinDateYear; y <= algorithm.MaxDateYear; y++ {
+		start := time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		yearDays.Insert(start.Unix() / 86400)
+		leapYears.Insert(time.Date(y, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay() - 365)
+	}
+	for leap := 0; leap < 2; leap++ {
+		for m := 0; m < 16; m++ {
+			days := 0
+			if m >= 1 && m <= 12 {
+				days = time.Date(2001+3*leap, time.Month(m), 1, 0, 0, 0, 0, time.UTC).YearDay() - 1
+			}
+			monthDays.Insert(days)
+		}
+	}
+	leap := leapYears.Lookup(year)[0]
+	days := api.Add(yearDays.Lookup(year)[0], monthDays.Lookup(api.Add(api.Mul(leap, 16), month))[0], fields[dateDay], -1)
+	seconds := api.Add(api.Mul(days, 86400), api.Mul(fields[dateHour], 3600), api.Mul(fields[dateMinute], 60), fields[dateSecond])
+	// The zone is ahead of UTC with '+' and behind with '-'.
+	offset := api.Add(api.Mul(fields[dateZoneHour], 3600), api.Mul(fields[dateZoneMinute], 60))
+	sign := api.Sub(1, api.Mul(2, api.IsZero(api.Sub(fields[dateZoneSign], '-'))))
+	return api.Sub(seconds, api.Mul(sign, offset)), nil
+}
@@ -0,0 +1,75 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/doubiliu/zk-email/algorithm"
+)
+
+type DateWrapper struct {
+	Date      PaddingSlice
+	Timestamp frontend.Variable `gnark:",public"`
+}
+
+func (c *DateWrapper) Define(api frontend.API) error {
+	sliceApi := NewSliceApi(api)
+	timestamp, err := sliceApi.ParseDate(c.Date)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(c.Timestamp, timestamp)
+	return nil
+}
+
+func TestParseDate(t *testing.T) {
+	assert := test.NewAssert(t)
+	length := 64
+	circuit := DateWrapper{Date: BytesToFixPadding(nil, false, length)}
+	for _, date := range []string{
+		"date:Thu, 30 Oct 2025 03:17:50 +0000 (GMT)\r\n",
+		"date:Fri, 7 Nov 2025 17:36:42 +0800\r\n",
+		"date:29 Feb 2024 23:59 -0530\r\n",
+	} {
+		timestamp, err := algorithm.ParseDate(date)
+		assert.NoError(err)
+		assignment := DateWrapper{Date: BytesToFixPadding([]byte(date), false, length), Timestamp: timestamp}
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err, date)
+		assignment.Timestamp = timestamp + 3600
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.Error(err, date)
+	}
+	// The zone must be numeric.
+	assignment := DateWrapper{Date: BytesToFixPadding([]byte("date:Thu, 30 Oct 2025 03:17:50 GMT\r\n"), false, length), Timestamp: 1761794270}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestDateTimestamp(t *testing.T) {
+	assert := test.NewAssert(t)
+	txtRecords, err := client.LookupTxt("1a1hai._domainkey.icloud.com.")
+	assert.NoError(err)
+	_, err = GetCustomDKIMVerifierWrapper("icloud", WithDateTimestamp())
+	assert.Error(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("icloud", WithRevealedHeaders("from", "date"), WithDateTimestamp())
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(ICloudTestData, txtRecords[0])
+	assert.NoError(err)
+	assert.Equal([]frontend.Variable{int64(1761794270)}, assignment.(*CustomDKIMVerifierWrapper[Mod1e2048]).Timestamp)
+	assert.Equal(0, len(assignment.(*CustomDKIMVerifierWrapper[Mod1e2048]).TimestampMask))
+}
+
+func TestDateHeaderBoundary(t *testing.T) {
+	assert := test.NewAssert(t)
+	// "Subject: meeting date:1 Jan 2099 00:00:00 +0000" ends with a date, but it is not the Date header.
+	forged := "date:1 Jan 2099 00:00:00 +0000\r\n"
+	assignment := forgedAssignment(assert, ForgedDateTestData, forged, newVerifierConfig(WithRevealedHeaders("date"), WithDateTimestamp()))
+	timestamp, err := algorithm.ParseDate(forged)
+	assert.NoError(err)
+	assignment.Timestamp = []frontend.Variable{timestamp}
+	err = test.IsSolved(assignment, assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
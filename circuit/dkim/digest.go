@@ -0,0 +1,50 @@
+package dkim
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// newBinaryHasher returns the in-circuit hasher of the DKIM signing hash function.
+func newBinaryHasher(api frontend.API, h crypto.Hash) (hash.BinaryHasher, error) {
+	switch h {
+	case crypto.SHA1:
+		return NewSha1(api)
+	case crypto.SHA256:
+		return sha2.New(api)
+	case crypto.SHA384:
+		return NewSha384(api)
+	case crypto.SHA512:
+		return NewSha512(api)
+	default:
+		return nil, fmt.Errorf("unsupported hash function %v", h)
+	}
+}
+
+// hashBytes computes the digest of the byte variables with the hash function h.
+func hashBytes(api frontend.API, h crypto.Hash, data []frontend.Variable) ([]frontend.Variable, error) {
+	hasher, err := newBinaryHasher(api, h)
+	if err != nil {
+		return nil, err
+	}
+	u8api, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	dataU8 := make([]uints.U8, len(data))
+	for i := 0; i < len(data); i++ {
+		dataU8[i] = u8api.ByteValueOf(data[i])
+	}
+	hasher.Write(dataU8)
+	ru8 := hasher.Sum()
+	r := make([]frontend.Variable, len(ru8))
+	for i := 0; i < len(r); i++ {
+		r[i] = ru8[i].Val
+	}
+	return r, nil
+}
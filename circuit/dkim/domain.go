@@ -0,0 +1,205 @@
+package dkim
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/doubiliu/zk-email/algorithm"
+)
+
+// Alignment selects how the d= domain of the DKIM signature must match the From domain, like the DMARC alignment.
+type Alignment int
+
+const (
+	// AlignmentNone does not relate the two domains, the From header need not be revealed.
+	// Any d= domain then backs any From header, it is the default when the From header is not revealed.
+	AlignmentNone Alignment = iota
+	// AlignmentRelaxed accepts a From domain equal to the d= domain or one of its subdomains.
+	// It is a plain suffix check without the public suffix list, not the organizational domain alignment
+	// of DMARC: d=example.com backs mail.example.com, but d=mail.example.com does not back example.com.
+	// It is the default when the From header is revealed.
+	AlignmentRelaxed
+	// AlignmentStrict requires the From domain to equal the d= domain.
+	AlignmentStrict
+)
+
+// String returns the name of the alignment.
+func (a Alignment) String() string {
+	switch a {
+	case AlignmentNone:
+		return "none"
+	case AlignmentRelaxed:
+		return "relaxed"
+	case AlignmentStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("Alignment(%d)", int(a))
+	}
+}
+
+// ParseAlignment returns the alignment of the name.
+func ParseAlignment(name string) (Alignment, error) {
+	for _, a := range []Alignment{AlignmentNone, AlignmentRelaxed, AlignmentStrict} {
+		if a.String() == name {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown domain alignment %s", name)
+}
+
+// signatureDomainPattern matches the relaxed canonical DKIM-Signature header trimmed after "b=",
+// the group captures the value of the d= tag. Tag values can not contain ';', so the tag is never
+// found inside another value.
+const signatureDomainPattern = `dkim-signature:(?:[^;]*;)* ?d ?= ?([!-:<-~]+) ?(?:;[^\r\n]*)?`
+
+// signatureDomainRegex is the compiled signatureDomainPattern.
+var signatureDomainRegex = mustCompileRegex(signatureDomainPattern)
+
+// ExtractSignatureDomain parses the d= domain of the trimmed DKIM-Signature header slice and returns it lowercased,
+// padded with trailing zeros to capacity bytes. captureMask marks the domain bytes, see SignatureDomainMask.
+func (c *SliceApi) ExtractSignatureDomain(trimmedHeader PaddingSlice, captureMask []frontend.Variable, capacity int) ([]frontend.Variable, error) {
+	domain, err := c.signatureDomainBytes(trimmedHeader, captureMask)
+	if err != nil {
+		return nil, err
+	}
+	return c.packCaptured(domain, c.nonZeroBits(domain), capacity, false), nil
+}
+
+// signatureDomainBytes returns the big-endian bytes of the trimmed header slice with the d= domain lowercased in place
+// and 0 elsewhere.
+func (c *SliceApi) signatureDomainBytes(trimmedHeader PaddingSlice, captureMask []frontend.Variable) ([]frontend.Variable, error) {
+	return c.signatureTagBytes(signatureDomainRegex, trimmedHeader, captureMask)
+}
+
+// signatureTagBytes returns the big-endian bytes of the trimmed header slice with the tag value captured by re
+// lowercased in place and 0 elsewhere.
+func (c *SliceApi) signatureTagBytes(re *Regex, trimmedHeader PaddingSlice, captureMask []frontend.Variable) ([]frontend.Variable, error) {
+	captured, err := c.AssertMatchRegex(re, trimmedHeader, captureMask)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]frontend.Variable, len(captured))
+	lower := newLowerTable(c.api)
+	for i := range captured {
+		value[i] = lower.Lookup(captured[i])[0]
+	}
+	if trimmedHeader.IsLittleEndian {
+		slices.Reverse(value)
+	}
+	return value, nil
+}
+
+// assertDomainAlignment asserts the alignment of the From domain and the d= domain, given as big-endian bytes
+// with the domain in place and 0 elsewhere. Both are right aligned in DomainCapacity bytes, a relaxed From domain
+// must end with the d= domain, preceded by a '.' or nothing.
+func (c *SliceApi) assertDomainAlignment(fromDomain, fromBits, sigDomain []frontend.Variable, a Alignment) {
+	api := c.api
+	from := c.packCaptured(fromDomain, fromBits, DomainCapacity, true)
+	sig := c.packCaptured(sigDomain, c.nonZeroBits(sigDomain), DomainCapacity, true)
+	if a == AlignmentStrict {
+		for i := range from {
+			api.AssertIsEqual(from[i], sig[i])
+		}
+		return
+	}
+	isEmpty := make([]frontend.Variable, len(sig))
+	for i := range sig {
+		isEmpty[i] = api.IsZero(sig[i])
+		api.AssertIsEqual(api.Mul(api.Sub(1, isEmpty[i]), api.Sub(from[i], sig[i])), 0)
+	}
+	for i := 0; i+1 < len(sig); i++ {
+		boundary := api.Mul(isEmpty[i], api.Sub(1, isEmpty[i+1]))
+		api.AssertIsEqual(api.Mul(boundary, api.Mul(from[i], api.Sub(from[i], '.'))), 0)
+	}
+}
+
+// assertSignatureDomain asserts that the d= domain, given as big-endian bytes with the domain in place and 0 elsewhere,
+// equals the lowercased domain.
+func (c *SliceApi) assertSignatureDomain(sigDomain []frontend.Variable, domain string) {
+	sig := c.packCaptured(sigDomain, c.nonZeroBits(sigDomain), DomainCapacity, false)
+	expected := make([]byte, DomainCapacity)
+	copy(expected, domain)
+	for i := range sig {
+		c.api.AssertIsEqual(sig[i], expected[i])
+	}
+}
+
+// SignatureDomain returns the lowercased d= domain of the DKIM signature of the email.
+func SignatureDomain(message string) (string, error) {
+	trimmedHeader, err := signatureTrimmedHeader(message)
+	if err != nil {
+		return "", err
+	}
+	return signatureDomain([]byte(trimmedHeader))
+}
+
+// signatureTrimmedHeader returns the canonical trimmed DKIM-Signature header of the email, see signedTrimmedHeader.
+func signatureTrimmedHeader(message string) (string, error) {
+	email := algorithm.ParseEmail(message)
+	for _, header := range email.Headers() {
+		if algorithm.IsSignatureHeader(header) {
+			signature, err := algorithm.ParseSignature(header)
+			if err != nil {
+				return "", err
+			}
+			return signedTrimmedHeader(signature), nil
+		}
+	}
+	return "", errors.New("no DKIM header found")
+}
+
+// signatureDomain parses the d= domain of the canonical trimmed DKIM-Signature header like the circuit.
+func signatureDomain(trimmedHeader []byte) (string, error) {
+	domain, ok := signatureTag(signatureDomainRegex, trimmedHeader)
+	if !ok {
+		return "", errors.New("no domain found in the DKIM signature")
+	}
+	return normalizeDomain(string(domain))
+}
+
+// signatureTag returns the tag value captured by re from the canonical trimmed DKIM-Signature header.
+func signatureTag(re *Regex, trimmedHeader []byte) ([]byte, bool) {
+	mask, ok := re.Capture(trimmedHeader)
+	if !ok {
+		return nil, false
+	}
+	value := make([]byte, 0)
+	for i := range mask {
+		if mask[i] {
+			value = append(value, trimmedHeader[i])
+		}
+	}
+	return value, true
+}
+
+// signedTrimmedHeader returns the canonical DKIM-Signature header up to "b=", which the circuit rebuilds
+// from SigPrefix, the body hash and SigSuffix.
+func signedTrimmedHeader(signature *algorithm.Signature) string {
+	trimmedHeader := signature.Canon().Header()(signature.TrimmedHeader())
+	return trimmedHeader[:strings.Index(trimmedHeader, "b=")+2]
+}
+
+// SignatureDomainMask returns the capture mask witness of the trimmed DKIM-Signature header padded to length bytes,
+// see ExtractSignatureDomain.
+func SignatureDomainMask(trimmedHeader []byte, length int) ([]frontend.Variable, error) {
+	mask, _, err := signatureDomainRegex.CaptureMask(trimmedHeader, length)
+	return mask, err
+}
+
+// CheckDomainAlignment checks the From domain against the d= domain of the DKIM signature like the circuit.
+func CheckDomainAlignment(fromDomain, sigDomain string, a Alignment) error {
+	if a == AlignmentNone {
+		return nil
+	}
+	fromDomain, sigDomain = strings.ToLower(fromDomain), strings.ToLower(sigDomain)
+	if len(fromDomain) > DomainCapacity || len(sigDomain) > DomainCapacity {
+		return errors.New("domain size is too big")
+	}
+	if fromDomain == sigDomain || (a == AlignmentRelaxed && strings.HasSuffix(fromDomain, "."+sigDomain)) {
+		return nil
+	}
+	return fmt.Errorf("from domain %s is not aligned with the signature domain %s", fromDomain, sigDomain)
+}
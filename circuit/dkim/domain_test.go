@@ -0,0 +1,139 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type DomainAlignmentWrapper struct {
+	From          PaddingSlice
+	FromMask      []frontend.Variable
+	TrimmedHeader PaddingSlice
+	SigMask       []frontend.Variable
+	Alignment     Alignment `gnark:"-"`
+}
+
+func (c *DomainAlignmentWrapper) Define(api frontend.API) error {
+	sliceApi := NewSliceApi(api)
+	address, domainBits, err := sliceApi.senderBytes(c.From, c.FromMask)
+	if err != nil {
+		return err
+	}
+	sigDomain, err := sliceApi.signatureDomainBytes(c.TrimmedHeader, c.SigMask)
+	if err != nil {
+		return err
+	}
+	sliceApi.assertDomainAlignment(sliceApi.selectBytes(address, domainBits), domainBits, sigDomain, c.Alignment)
+	return nil
+}
+
+func TestSignatureDomain(t *testing.T) {
+	assert := test.NewAssert(t)
+	domain, err := SignatureDomain(GmailTestData)
+	assert.NoError(err)
+	assert.Equal("gmail.com", domain)
+	domain, err = SignatureDomain(FoxmailTestData)
+	assert.NoError(err)
+	assert.Equal("foxmail.com", domain)
+	// Tags whose names end with d are not the d= tag.
+	domain, err = signatureDomain([]byte("dkim-signature:v=1; darn=qq.com; d=Example.com; bh=; b="))
+	assert.NoError(err)
+	assert.Equal("example.com", domain)
+}
+
+func TestCheckDomainAlignment(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.NoError(CheckDomainAlignment("foxmail.com", "FoxMail.com", AlignmentStrict))
+	assert.NoError(CheckDomainAlignment("mail.foxmail.com", "foxmail.com", AlignmentRelaxed))
+	assert.Error(CheckDomainAlignment("mail.foxmail.com", "foxmail.com", AlignmentStrict))
+	assert.Error(CheckDomainAlignment("evilfoxmail.com", "foxmail.com", AlignmentRelaxed))
+	assert.Error(CheckDomainAlignment("foxmail.com", "mail.foxmail.com", AlignmentRelaxed))
+	assert.NoError(CheckDomainAlignment("qq.com", "foxmail.com", AlignmentNone))
+	alignment, err := ParseAlignment("strict")
+	assert.NoError(err)
+	assert.Equal(AlignmentStrict, alignment)
+}
+
+func TestDomainAlignment(t *testing.T) {
+	assert := test.NewAssert(t)
+	fromLength, sigLength := 48, 80
+	trimmedHeader := []byte("dkim-signature:v=1; a=rsa-sha256; d=foxmail.com; bh=abc=; b=")
+	sigMask, err := SignatureDomainMask(trimmedHeader, sigLength)
+	assert.NoError(err)
+	for _, tc := range []struct {
+		from      string
+		alignment Alignment
+		valid     bool
+	}{
+		{"from:Bob <bob@FoxMail.com>\r\n", AlignmentStrict, true},
+		{"from:bob@foxmail.com\r\n", AlignmentRelaxed, true},
+		{"from:bob@mail.foxmail.com\r\n", AlignmentRelaxed, true},
+		{"from:bob@mail.foxmail.com\r\n", AlignmentStrict, false},
+		{"from:bob@evilfoxmail.com\r\n", AlignmentRelaxed, false},
+		{"from:bob@qq.com\r\n", AlignmentRelaxed, false},
+	} {
+		circuit := DomainAlignmentWrapper{
+			From:          BytesToFixPadding(nil, false, fromLength),
+			FromMask:      make([]frontend.Variable, fromLength),
+			TrimmedHeader: BytesToFixPadding(nil, false, sigLength),
+			SigMask:       make([]frontend.Variable, sigLength),
+			Alignment:     tc.alignment,
+		}
+		fromMask, err := SenderMask([]byte(tc.from), fromLength)
+		assert.NoError(err)
+		assignment := DomainAlignmentWrapper{
+			From:          BytesToFixPadding([]byte(tc.from), false, fromLength),
+			FromMask:      fromMask,
+			TrimmedHeader: BytesToFixPadding(trimmedHeader, false, sigLength),
+			SigMask:       sigMask,
+		}
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		if tc.valid {
+			assert.NoError(err, tc.from)
+		} else {
+			assert.Error(err, tc.from)
+		}
+	}
+	// The assignment checks the alignment before proving.
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithDomainAlignment(AlignmentStrict))
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.NoError(err)
+	assert.Equal(len(verifier.(*CustomDKIMVerifierWrapper[Mod1e1024]).SignatureDomainMask), len(assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).SignatureDomainMask))
+	// A revealed From header is aligned by default, the alignment requires the revealed From header.
+	verifier, err = GetCustomDKIMVerifierWrapper("foxmail")
+	assert.NoError(err)
+	assert.Equal(AlignmentRelaxed, verifier.(*CustomDKIMVerifierWrapper[Mod1e1024]).Config.DomainAlignment)
+	verifier, err = GetCustomDKIMVerifierWrapper("foxmail", WithRevealedHeaders("subject"))
+	assert.NoError(err)
+	assert.Equal(AlignmentNone, verifier.(*CustomDKIMVerifierWrapper[Mod1e1024]).Config.DomainAlignment)
+	verifier, err = GetCustomDKIMVerifierWrapper("foxmail", WithDomainAlignment(AlignmentNone))
+	assert.NoError(err)
+	assert.Equal(AlignmentNone, verifier.(*CustomDKIMVerifierWrapper[Mod1e1024]).Config.DomainAlignment)
+	_, err = GetCustomDKIMVerifierWrapper("foxmail", WithRevealedHeaders("subject"), WithDomainAlignment(AlignmentRelaxed))
+	assert.Error(err)
+}
+
+func TestDomainAlignmentCanonicalization(t *testing.T) {
+	assert := test.NewAssert(t)
+	txtRecord := lookupTestRecord(assert, SimpleTestData)
+	// Emails of the simple canonicalization are proven without the alignment.
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithDomainAlignment(AlignmentNone))
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(SimpleTestData, txtRecord)
+	assert.NoError(err)
+	err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// The header patterns of the circuit only match the relaxed canonicalization.
+	for _, opts := range [][]Option{nil, {WithDomainAlignment(AlignmentNone), WithKeyName()}, {WithRevealedHeaders("subject"), WithTimestamp()}} {
+		verifier, err = GetCustomDKIMVerifierWrapper("foxmail", opts...)
+		assert.NoError(err)
+		_, err = verifier.NewAssignment(SimpleTestData, txtRecord)
+		assert.Error(err)
+	}
+}
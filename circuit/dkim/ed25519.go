@@ -0,0 +1,202 @@
+package dkim
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/doubiliu/zk-email/algorithm"
+)
+
+// Ed25519PublicKey is the point A of an Ed25519 public key in affine coordinates.
+type Ed25519PublicKey struct {
+	X emulated.Element[Ed25519Fp]
+	Y emulated.Element[Ed25519Fp]
+}
+
+// Ed25519 verifies the Ed25519 signatures of RFC 8032 with the emulated arithmetic of edwards25519.
+type Ed25519 struct {
+	api frontend.API
+	fp  *emulated.Field[Ed25519Fp]
+	fr  *emulated.Field[Ed25519Fr]
+}
+
+// NewEd25519 returns an Ed25519 verifier.
+func NewEd25519(api frontend.API) (*Ed25519, error) {
+	fp, err := emulated.NewField[Ed25519Fp](api)
+	if err != nil {
+		return nil, err
+	}
+	fr, err := emulated.NewField[Ed25519Fr](api)
+	if err != nil {
+		return nil, err
+	}
+	return &Ed25519{api: api, fp: fp, fr: fr}, nil
+}
+
+// edwardsPoint is an affine point of edwards25519.
+type edwardsPoint struct {
+	x, y *emulated.Element[Ed25519Fp]
+}
+
+// Verify verifies the 64-byte Ed25519 signature R || S of the message with the public key, like ed25519.Verify.
+// S must be lower than the group order and R must be the canonical encoding of [S]B - [k]A.
+func (ed *Ed25519) Verify(pubKey *Ed25519PublicKey, sign, message []frontend.Variable) error {
+	api, fr := ed.api, ed.fr
+	if len(sign) != ed25519.SignatureSize {
+		return errors.New("Ed25519 signature must be 64 bytes")
+	}
+	a := edwardsPoint{x: &pubKey.X, y: &pubKey.Y}
+	ed.assertOnCurve(a)
+	// k = SHA-512(R || A || M) as a little-endian integer modulo the group order.
+	data := slices.Concat(sign[:32], ed.EncodePublicKey(pubKey), message)
+	digest, err := hashBytes(api, crypto.SHA512, data)
+	if err != nil {
+		return err
+	}
+	digestBits := BytesToBits(api, reversed(digest))
+	lo := fr.FromBits(digestBits[:256]...)
+	hi := fr.FromBits(digestBits[256:]...)
+	shift := new(big.Int).Lsh(big.NewInt(1), 256)
+	k := fr.Add(lo, fr.Mul(hi, fr.NewElement(shift.Mod(shift, ed25519L))))
+	kBits := fr.ToBitsCanonical(k)
+	// S is canonical if its bits equal the bits of its reduction.
+	sBits := BytesToBits(api, reversed(sign[32:]))
+	sCanonical := fr.ToBitsCanonical(fr.FromBits(sBits...))
+	for i := range sBits {
+		if i < len(sCanonical) {
+			api.AssertIsEqual(sBits[i], sCanonical[i])
+		} else {
+			api.AssertIsEqual(sBits[i], 0)
+		}
+	}
+	// Q = [S]B + [k](-A) by the joint double-and-add over the bits of S and k.
+	negA := edwardsPoint{x: ed.fp.Neg(a.x), y: a.y}
+	base := edwardsPoint{x: ed.fp.NewElement(ed25519BaseX), y: ed.fp.NewElement(ed25519BaseY)}
+	table := [4]edwardsPoint{{x: ed.fp.Zero(), y: ed.fp.One()}, base, negA, ed.add(base, negA)}
+	n := len(kBits)
+	q := ed.lookup(sBits[n-1], kBits[n-1], table)
+	for i := n - 2; i >= 0; i-- {
+		q = ed.add(ed.double(q), ed.lookup(sBits[i], kBits[i], table))
+	}
+	r := ed.encode(q)
+	for i := range r {
+		api.AssertIsEqual(r[i], sign[i])
+	}
+	return nil
+}
+
+// EncodePublicKey returns the 32-byte encoding of the public key, which is the key of the DNS record.
+func (ed *Ed25519) EncodePublicKey(pubKey *Ed25519PublicKey) []frontend.Variable {
+	return ed.encode(edwardsPoint{x: &pubKey.X, y: &pubKey.Y})
+}
+
+// encode returns the little-endian y with the parity of x in the top bit, see RFC 8032 section 5.1.2.
+func (ed *Ed25519) encode(p edwardsPoint) []frontend.Variable {
+	yBits := ed.fp.ToBitsCanonical(p.y)
+	xBits := ed.fp.ToBitsCanonical(p.x)
+	return reversed(BitsToBytes(ed.api, append(yBits[:255:255], xBits[0])))
+}
+
+// assertOnCurve asserts -x^2+y^2 = 1+d*x^2*y^2, on which the addition formulas are complete.
+func (ed *Ed25519) assertOnCurve(p edwardsPoint) {
+	fp := ed.fp
+	xx, yy := fp.Mul(p.x, p.x), fp.Mul(p.y, p.y)
+	fp.AssertIsEqual(fp.Sub(yy, xx), fp.Add(fp.One(), fp.Mul(fp.NewElement(ed25519D), fp.Mul(xx, yy))))
+}
+
+// add adds the points with the complete affine formulas of the twisted Edwards curve with a = -1,
+// the denominators are never zero as d is not a square.
+func (ed *Ed25519) add(p, q edwardsPoint) edwardsPoint {
+	fp := ed.fp
+	x1y2, y1x2 := fp.Mul(p.x, q.y), fp.Mul(p.y, q.x)
+	x1x2, y1y2 := fp.Mul(p.x, q.x), fp.Mul(p.y, q.y)
+	dxy := fp.Mul(fp.NewElement(ed25519D), fp.Mul(x1x2, y1y2))
+	return edwardsPoint{
+		x: fp.Div(fp.Add(x1y2, y1x2), fp.Add(fp.One(), dxy)),
+		y: fp.Div(fp.Add(y1y2, x1x2), fp.Sub(fp.One(), dxy)),
+	}
+}
+
+// double doubles the point, the denominators y^2-x^2 and 2+x^2-y^2 are 1+d*x^2*y^2 and 1-d*x^2*y^2 on the curve.
+func (ed *Ed25519) double(p edwardsPoint) edwardsPoint {
+	fp := ed.fp
+	xx, yy, xy := fp.Mul(p.x, p.x), fp.Mul(p.y, p.y), fp.Mul(p.x, p.y)
+	return edwardsPoint{
+		x: fp.Div(fp.Add(xy, xy), fp.Sub(yy, xx)),
+		y: fp.Div(fp.Add(yy, xx), fp.Sub(fp.Add(fp.NewElement(2), xx), yy)),
+	}
+}
+
+// lookup returns table[b0+2*b1].
+func (ed *Ed25519) lookup(b0, b1 frontend.Variable, table [4]edwardsPoint) edwardsPoint {
+	return edwardsPoint{
+		x: ed.fp.Lookup2(b0, b1, table[0].x, table[1].x, table[2].x, table[3].x),
+		y: ed.fp.Lookup2(b0, b1, table[0].y, table[1].y, table[2].y, table[3].y),
+	}
+}
+
+// reversed returns the variables in reverse order, it converts between little-endian and big-endian bytes.
+func reversed(src []frontend.Variable) []frontend.Variable {
+	result := slices.Clone(src)
+	slices.Reverse(result)
+	return result
+}
+
+// parseEd25519PublicKey parses the 32-byte Ed25519 public key from the DNS TXT record.
+func parseEd25519PublicKey(txtRecord string) ([]byte, error) {
+	key := algorithm.ParsePubkey(txtRecord)
+	if key.KeyType() != "ed25519" {
+		return nil, errors.New("not an Ed25519 public key")
+	}
+	if len(key.Key()) != ed25519.PublicKeySize {
+		return nil, errors.New("Ed25519 public key must be 32 bytes")
+	}
+	return key.Key(), nil
+}
+
+// ed25519Point decodes the Ed25519 public key to its affine coordinates, see RFC 8032 section 5.1.3.
+// Only canonical encodings can be proven, as the circuit hashes the encoding of the point.
+func ed25519Point(key []byte) (*big.Int, *big.Int, error) {
+	encoded := slices.Clone(key)
+	slices.Reverse(encoded)
+	sign := encoded[0] >> 7
+	encoded[0] &= 0x7f
+	y := new(big.Int).SetBytes(encoded)
+	if y.Cmp(ed25519P) >= 0 {
+		return nil, nil, errors.New("non-canonical Ed25519 public key")
+	}
+	// x^2 = (y^2-1)/(d*y^2+1)
+	yy := new(big.Int).Mul(y, y)
+	u := new(big.Int).Sub(yy, big.NewInt(1))
+	v := new(big.Int).Mul(ed25519D, yy)
+	v.Add(v, big.NewInt(1))
+	xx := u.Mul(u, v.ModInverse(v, ed25519P))
+	x := new(big.Int).ModSqrt(xx.Mod(xx, ed25519P), ed25519P)
+	if x == nil {
+		return nil, nil, errors.New("Ed25519 public key is not on the curve")
+	}
+	if x.Sign() == 0 && sign == 1 {
+		return nil, nil, errors.New("non-canonical Ed25519 public key")
+	}
+	if uint8(x.Bit(0)) != sign {
+		x.Sub(ed25519P, x)
+	}
+	return x, y, nil
+}
+
+// newEd25519PublicKey returns the public key of the circuit from the 32-byte Ed25519 public key.
+func newEd25519PublicKey(key []byte) (Ed25519PublicKey, error) {
+	x, y, err := ed25519Point(key)
+	if err != nil {
+		return Ed25519PublicKey{}, err
+	}
+	return Ed25519PublicKey{
+		X: emulated.ValueOf[Ed25519Fp](x),
+		Y: emulated.ValueOf[Ed25519Fp](y),
+	}, nil
+}
@@ -0,0 +1,46 @@
+package dkim
+
+import (
+	"math/big"
+)
+
+// Ed25519Fp provides type parametrization for emulated arithmetic over the base field of edwards25519:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The modulus is 2^255-19.
+type Ed25519Fp struct{}
+
+func (Ed25519Fp) NbLimbs() uint     { return 4 }
+func (Ed25519Fp) BitsPerLimb() uint { return 64 }
+func (Ed25519Fp) IsPrime() bool     { return true }
+func (Ed25519Fp) Modulus() *big.Int {
+	return new(big.Int).Set(ed25519P)
+}
+
+// Ed25519Fr provides type parametrization for emulated arithmetic over the scalar field of edwards25519:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The modulus is the order of the base point, 2^252+27742317777372353535851937790883648493.
+type Ed25519Fr struct{}
+
+func (Ed25519Fr) NbLimbs() uint     { return 4 }
+func (Ed25519Fr) BitsPerLimb() uint { return 64 }
+func (Ed25519Fr) IsPrime() bool     { return true }
+func (Ed25519Fr) Modulus() *big.Int {
+	return new(big.Int).Set(ed25519L)
+}
+
+var (
+	ed25519P, _     = new(big.Int).SetString("57896044618658097711785492504343953926634992332820282019728792003956564819949", 10)
+	ed25519L, _     = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	ed25519BaseX, _ = new(big.Int).SetString("15112221349535400772501151409588531511454012693041857206046113283949847762202", 10)
+	ed25519BaseY, _ = new(big.Int).SetString("46316835694926478169428394003475163141307993866256225615783033603165251855960", 10)
+	// ed25519D is the curve constant d = -121665/121666 of -x^2+y^2 = 1+d*x^2*y^2.
+	ed25519D = func() *big.Int {
+		d := new(big.Int).ModInverse(big.NewInt(121666), ed25519P)
+		d.Mul(d, big.NewInt(-121665))
+		return d.Mod(d, ed25519P)
+	}()
+)
@@ -0,0 +1,137 @@
+package dkim
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/doubiliu/zk-email/algorithm"
+	"github.com/doubiliu/zk-email/utils"
+)
+
+// Ed25519TestData is signed locally with the key of ed25519TestSeed by algorithm.SignEmail, see TestEd25519TestData.
+var Ed25519TestData = utils.FixupNewlines(`DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;
+	d=example.com; s=ed25519;
+	h=from:to:subject:date:message-id;
+	bh=DD3L33E64/RV0CUJdfGTtPGKU7OPJpo/2LxOdf3k0RI=;
+	b=xnWo3vpauSNMkvZ++B3XMi8PrjKg8KitL5neGmL5P9xDbP49w76a0juXMNaXkKC4wLLzJNzaDHWcJcvxSSpuAg==
+From: Alice <alice@example.com>
+To: Bob <bob@example.org>
+Subject: Approve 0x1
+Date: Mon, 19 Oct 2026 10:00:00 +0000
+Message-ID: <20261019100000.1@example.com>
+
+Hello Bob.
+`)
+
+var ed25519TestSeed = []byte("zk-email ed25519 test fixture 01")
+
+var ed25519TxtRecord = `v=DKIM1; k=ed25519; p=Ja30PHXOM9Nj3W3X6GXuaV7BUgY7MHOnTO04Q2LIPQo=`
+
+var Ed25519Template = utils.FixupNewlines(`From: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+To: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Subject: xxxxxxxxxxxxxxx
+Date: xxx, xx xxx xxxx xx:xx:xx +xxxx
+Message-ID: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed; d=example.com; s=xxxxxxxxxx; h=from:to:subject:date:message-id; bh=DD3L33E64/RV0CUJdfGTtPGKU7OPJpo/2LxOdf3k0RI=; b=xnWo3vpauSNMkvZ++B3XMi8PrjKg8KitL5neGmL5P9xDbP49w76a0juXMNaXkKC4wLLzJNzaDHWcJcvxSSpuAg==`)
+
+func TestEd25519TestData(t *testing.T) {
+	assert := test.NewAssert(t)
+	privateKey := ed25519.NewKeyFromSeed(ed25519TestSeed)
+	assert.Equal(ed25519TxtRecord, algorithm.Ed25519TxtRecord(privateKey.Public().(ed25519.PublicKey)))
+	unsigned := utils.FixupNewlines(`From: Alice <alice@example.com>
+To: Bob <bob@example.org>
+Subject: Approve 0x1
+Date: Mon, 19 Oct 2026 10:00:00 +0000
+Message-ID: <20261019100000.1@example.com>
+
+Hello Bob.
+`)
+	mail, err := algorithm.SignEmail(unsigned, "example.com", "ed25519", []string{"from", "to", "subject", "date", "message-id"}, privateKey)
+	assert.NoError(err)
+	assert.Equal(Ed25519TestData, mail)
+}
+
+type Ed25519Wrapper struct {
+	PublicKey Ed25519PublicKey
+	Signature []frontend.Variable
+	Message   []frontend.Variable
+}
+
+func (c *Ed25519Wrapper) Define(api frontend.API) error {
+	ed, err := NewEd25519(api)
+	if err != nil {
+		return err
+	}
+	return ed.Verify(&c.PublicKey, c.Signature, c.Message)
+}
+
+func TestEd25519Verify(t *testing.T) {
+	assert := test.NewAssert(t)
+	privateKey := ed25519.NewKeyFromSeed(ed25519TestSeed)
+	publicKey, err := newEd25519PublicKey(privateKey.Public().(ed25519.PublicKey))
+	assert.NoError(err)
+	message := sha256.Sum256([]byte("dkim-signature:v=1; a=ed25519-sha256; b="))
+	signature := ed25519.Sign(privateKey, message[:])
+	circuit := Ed25519Wrapper{
+		PublicKey: publicKey,
+		Signature: BytesToFrontVariable(signature),
+		Message:   BytesToFrontVariable(message[:]),
+	}
+	assignment := circuit
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// S+L is rejected like by ed25519.Verify, so that signatures are not malleable.
+	s := slices.Clone(signature[32:])
+	slices.Reverse(s)
+	sum := new(big.Int).Add(new(big.Int).SetBytes(s), ed25519L).FillBytes(make([]byte, 32))
+	slices.Reverse(sum)
+	malleable := slices.Concat(signature[:32], sum)
+	assert.False(ed25519.Verify(privateKey.Public().(ed25519.PublicKey), message[:], malleable))
+	assignment.Signature = BytesToFrontVariable(malleable)
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+	// A signature of another message is rejected.
+	other := ed25519.Sign(privateKey, []byte("another message"))
+	assignment.Signature = BytesToFrontVariable(other)
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestEd25519DKIMVerifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	verifier, err := NewCustomDKIMVerifierWrapper(MailTemplate{Header: Ed25519Template})
+	assert.NoError(err)
+	assert.Equal(256, verifier.KeyBits())
+	assignment, err := verifier.NewAssignment(Ed25519TestData, ed25519TxtRecord)
+	assert.NoError(err)
+	err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	result := assignment.(*CustomDKIMVerifierWrapper[Ed25519Fp])
+	keyHash, err := verifier.PublicKeyHash(ed25519TxtRecord)
+	assert.NoError(err)
+	assert.Equal(BigIntsToFrontVariable(keyHash), result.PubKeyHash)
+	nullifier, err := EmailNullifier(Ed25519TestData, CommitmentSHA256, verifier.KeyBits())
+	assert.NoError(err)
+	assert.Equal(nullifier, result.Nullifier)
+	// The key of the assignment must be the key of the DNS record.
+	otherKey := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	otherAssignment, err := verifier.NewAssignment(Ed25519TestData, algorithm.Ed25519TxtRecord(otherKey.Public().(ed25519.PublicKey)))
+	assert.NoError(err)
+	err = test.IsSolved(verifier, otherAssignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+	// RSA mails and keys do not fit the Ed25519 circuit.
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	_, err = verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.Error(err)
+	_, err = verifier.NewAssignment(Ed25519TestData, txtRecords[0])
+	assert.Error(err)
+	_, err = NewCustomDKIMVerifierWrapper(MailTemplate{Header: Ed25519Template}, WithFixedExponent(DefaultExponent))
+	assert.Error(err)
+}
@@ -1,13 +1,20 @@
 package dkim
 
 import (
+	"crypto"
+	"errors"
+	"strings"
+
 	"github.com/consensys/gnark/frontend"
 )
 
+// CustomEmailHeader splits the signed headers into hidden parts and revealed headers,
+// the full header is HiddenData[0] || SpecifyData[0] || HiddenData[1] || ... || SpecifyData[n-1] || HiddenData[n].
 type CustomEmailHeader struct {
-	PrefixData  PaddingSlice
-	SpecifyData PaddingSlice
-	SuffixData  PaddingSlice
+	// HiddenData are the headers between the revealed ones, there is one more hidden part than revealed headers.
+	HiddenData []PaddingSlice
+	// SpecifyData are the revealed headers, each one is hashed into the public input commitment.
+	SpecifyData []PaddingSlice
 }
 
 func NewCustomEmailHeaderEncode(api frontend.API) CustomEmailHeaderEncode {
@@ -20,22 +27,51 @@ type CustomEmailHeaderEncode struct {
 
 // Encode concatenates the email header parts into a single PaddingSlice.
 func (ce CustomEmailHeaderEncode) Encode(header CustomEmailHeader) (PaddingSlice, error) {
+	if len(header.HiddenData) != len(header.SpecifyData)+1 {
+		return PaddingSlice{}, errors.New("hidden data must surround every specify data")
+	}
 	sliceApi := NewSliceApi(ce.api)
-	resultSlice := header.PrefixData
-	resultSlice = sliceApi.concat(resultSlice, header.SpecifyData, resultSlice.IsLittleEndian)
-	resultSlice = sliceApi.concat(resultSlice, header.SuffixData, resultSlice.IsLittleEndian)
+	resultSlice := header.HiddenData[0]
+	for i := range header.SpecifyData {
+		resultSlice = sliceApi.concat(resultSlice, header.SpecifyData[i], resultSlice.IsLittleEndian)
+		resultSlice = sliceApi.concat(resultSlice, header.HiddenData[i+1], resultSlice.IsLittleEndian)
+	}
 	return resultSlice, nil
 }
 
-// GetHeaderHash computes the hash of the full email header with trimmed parts.
+// AssertHeaderBoundaries asserts that every revealed header is a whole header of the encoded header,
+// so that a revealed header can not be cut out of the middle of another one, like "from:" within the subject.
+// The hidden part before it is empty or ends with "\r\n", the revealed header starts with its lowercase name,
+// see names, and a colon, and it ends with "\r\n". The name is compared case-insensitively for the simple headers.
+func (ce CustomEmailHeaderEncode) AssertHeaderBoundaries(header CustomEmailHeader, names []string) error {
+	if len(header.HiddenData) != len(header.SpecifyData)+1 || len(names) != len(header.SpecifyData) {
+		return errors.New("revealed headers do not match their names")
+	}
+	sliceApi := NewSliceApi(ce.api)
+	lineBreak := []byte("\r\n")
+	for i := range header.SpecifyData {
+		hidden := header.HiddenData[i]
+		ce.api.AssertIsEqual(ce.api.Or(sliceApi.IsEmpty(hidden), sliceApi.HasSuffix(hidden, lineBreak)), 1)
+		sliceApi.AssertHasPrefix(sliceApi.ToLower(header.SpecifyData[i]), []byte(strings.ToLower(names[i])+":"))
+		sliceApi.AssertHasSuffix(header.SpecifyData[i], lineBreak)
+	}
+	return nil
+}
+
+// GetHeaderHash computes the SHA-256 hash of the full email header with trimmed parts.
 func (ce CustomEmailHeaderEncode) GetHeaderHash(header CustomEmailHeader, trimmedHeader PaddingSlice) ([]frontend.Variable, error) {
+	return ce.GetHeaderDigest(header, trimmedHeader, crypto.SHA256)
+}
+
+// GetHeaderDigest computes the hash of the full email header with trimmed parts using the hash function h.
+func (ce CustomEmailHeaderEncode) GetHeaderDigest(header CustomEmailHeader, trimmedHeader PaddingSlice, h crypto.Hash) ([]frontend.Variable, error) {
 	sliceApi := NewSliceApi(ce.api)
 	resultSlice, err := ce.Encode(header)
 	if err != nil {
 		return nil, err
 	}
 	resultSlice = sliceApi.concat(resultSlice, trimmedHeader, resultSlice.IsLittleEndian)
-	resultHash, err := resultSlice.GetSliceHash(ce.api)
+	resultHash, err := resultSlice.GetSliceDigest(ce.api, h)
 	if err != nil {
 		return nil, err
 	}
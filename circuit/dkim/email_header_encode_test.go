@@ -73,9 +73,11 @@ func TestCustomEmailHeaderEncode(t *testing.T) {
 	suffixData := contentType
 
 	ch := CustomEmailHeader{
-		PrefixData:  BytesToFixPadding(predixData, false, len(predixData)),
-		SpecifyData: BytesToFixPadding(specifyData, false, len(specifyData)),
-		SuffixData:  BytesToFixPadding(suffixData, false, len(suffixData)),
+		HiddenData: []PaddingSlice{
+			BytesToFixPadding(predixData, false, len(predixData)),
+			BytesToFixPadding(suffixData, false, len(suffixData)),
+		},
+		SpecifyData: []PaddingSlice{BytesToFixPadding(specifyData, false, len(specifyData))},
 	}
 	expect := BytesToFrontVariable(headersHash)
 	circuit := CustomEmailHeaderWrapper{
@@ -91,3 +93,49 @@ func TestCustomEmailHeaderEncode(t *testing.T) {
 	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
 	assert.NoError(err)
 }
+
+type HeaderBoundariesWrapper struct {
+	Header CustomEmailHeader
+	Names  []string `gnark:"-"`
+}
+
+func (c *HeaderBoundariesWrapper) Define(api frontend.API) error {
+	return NewCustomEmailHeaderEncode(api).AssertHeaderBoundaries(c.Header, c.Names)
+}
+
+func TestAssertHeaderBoundaries(t *testing.T) {
+	assert := test.NewAssert(t)
+	length := 48
+	circuit := HeaderBoundariesWrapper{
+		Header: CustomEmailHeader{
+			HiddenData:  []PaddingSlice{BytesToFixPadding(nil, false, length), BytesToFixPadding(nil, false, length)},
+			SpecifyData: []PaddingSlice{BytesToFixPadding(nil, false, length)},
+		},
+		Names: []string{"From"},
+	}
+	for _, c := range []struct {
+		hidden, from string
+		solved       bool
+	}{
+		{"", "from:bob@qq.com\r\n", true},
+		{"to:alice@qq.com\r\n", "from:bob@qq.com\r\n", true},
+		{"to:alice@qq.com\r\n", "From: bob@qq.com\r\n", true},
+		// The From header is cut out of the subject.
+		{"subject:hi ", "from:bob@qq.com\r\n", false},
+		{"to:alice@qq.com\r\n", "subject:from:bob@qq.com\r\n", false},
+		{"to:alice@qq.com\r\n", "from:bob@qq.com", false},
+	} {
+		assignment := HeaderBoundariesWrapper{
+			Header: CustomEmailHeader{
+				HiddenData:  []PaddingSlice{BytesToFixPadding([]byte(c.hidden), false, length), BytesToFixPadding([]byte("date:1 Jan 2025 00:00 +0000\r\n"), false, length)},
+				SpecifyData: []PaddingSlice{BytesToFixPadding([]byte(c.from), false, length)},
+			},
+		}
+		err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		if c.solved {
+			assert.NoError(err, c.hidden+c.from)
+		} else {
+			assert.Error(err, c.hidden+c.from)
+		}
+	}
+}
@@ -6,7 +6,7 @@ import (
 
 type EmailSig struct {
 	SigPrefix  PaddingSlice
-	BodyHash   [32]frontend.Variable
+	BodyHash   []frontend.Variable // digest of the signing hash function
 	SigSuffix  PaddingSlice
 	SigContent []frontend.Variable
 }
@@ -22,7 +22,7 @@ type EmailSigEncode struct {
 // GetTrimmedHeader constructs the trimmed email header used for signature verification.
 func (es EmailSigEncode) GetTrimmedHeader(sig EmailSig) (PaddingSlice, error) {
 	b64encoder := NewBase64Encode(es.api)
-	boyHashinB64 := b64encoder.EncodeRule2(sig.BodyHash[:])
+	boyHashinB64 := b64encoder.Encode(sig.BodyHash)
 	tempSlice := PaddingSlice{
 		IsLittleEndian: false,
 		Padding:        frontend.Variable(-1),
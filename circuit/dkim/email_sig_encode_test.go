@@ -50,7 +50,7 @@ func TestEmailSigEncode(t *testing.T) {
 	trimmedHash := hasher.Sum(nil)
 	SigPrefix := testTrimmedHeader[0 : strings.Index(testTrimmedHeader, "bh=")+3]
 	SigSuffix := testTrimmedHeader[strings.Index(testTrimmedHeader, "bh=")+3+len(testBodyHash) : strings.Index(testTrimmedHeader, "b=")+2]
-	bodyHash := [32]frontend.Variable{}
+	bodyHash := make([]frontend.Variable, 32)
 	for i := range bodyHash {
 		bodyHash[i] = tBodyHash[i]
 	}
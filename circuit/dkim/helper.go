@@ -2,6 +2,7 @@ package dkim
 
 import (
 	"errors"
+	"math/big"
 	"slices"
 
 	"github.com/consensys/gnark/frontend"
@@ -54,6 +55,15 @@ func BytesToPadding(src []byte, isLittleEndian bool, padding int) PaddingSlice {
 	}
 }
 
+// bytesToPaddings converts byte arrays to PaddingSlices without padding.
+func bytesToPaddings(src [][]byte) []PaddingSlice {
+	result := make([]PaddingSlice, len(src))
+	for i := range result {
+		result[i] = BytesToPadding(src[i], false, -1)
+	}
+	return result
+}
+
 // BytesToFixPadding converts a byte array to a PaddingSlice with fixed length by adding leading zeros.
 func BytesToFixPadding(src []byte, isLittleEndian bool, maxLength int) PaddingSlice {
 	if len(src) > maxLength {
@@ -77,3 +87,37 @@ func GetHash(params ...[]byte) []byte {
 	}
 	return sha256.Sum(nil)
 }
+
+// PubInputChunkSize is the byte length packed into one public input, 16 bytes always fit into the BN254 scalar field.
+const PubInputChunkSize = 16
+
+// PackBytes packs big-endian bytes into field elements of PubInputChunkSize bytes, the leading bytes come first.
+func PackBytes(src []byte) []*big.Int {
+	result := make([]*big.Int, 0, (len(src)+PubInputChunkSize-1)/PubInputChunkSize)
+	for i := 0; i < len(src); i += PubInputChunkSize {
+		result = append(result, new(big.Int).SetBytes(src[i:min(i+PubInputChunkSize, len(src))]))
+	}
+	return result
+}
+
+// packBytes packs big-endian byte variables like PackBytes, the bytes must already be range checked.
+func packBytes(api frontend.API, src []frontend.Variable) []frontend.Variable {
+	result := make([]frontend.Variable, 0, (len(src)+PubInputChunkSize-1)/PubInputChunkSize)
+	for i := 0; i < len(src); i += PubInputChunkSize {
+		chunk := frontend.Variable(0)
+		for _, b := range src[i:min(i+PubInputChunkSize, len(src))] {
+			chunk = api.Add(api.Mul(chunk, 256), b)
+		}
+		result = append(result, chunk)
+	}
+	return result
+}
+
+// BigIntsToFrontVariable converts a big.Int array to a frontend.Variable array.
+func BigIntsToFrontVariable(src []*big.Int) []frontend.Variable {
+	result := make([]frontend.Variable, len(src))
+	for i := range result {
+		result[i] = src[i]
+	}
+	return result
+}
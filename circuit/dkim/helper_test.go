@@ -0,0 +1,41 @@
+package dkim
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type PackBytesWrapper struct {
+	Data   []frontend.Variable
+	Packed []frontend.Variable
+}
+
+func (c *PackBytesWrapper) Define(api frontend.API) error {
+	packed := packBytes(api, c.Data)
+	for i := range c.Packed {
+		api.AssertIsEqual(c.Packed[i], packed[i])
+	}
+	return nil
+}
+
+func TestPackBytes(t *testing.T) {
+	assert := test.NewAssert(t)
+	digest := sha256.Sum256([]byte("foo"))
+	packed := PackBytes(digest[:])
+	assert.Equal(2, len(packed))
+	assert.Equal(digest[:PubInputChunkSize], packed[0].FillBytes(make([]byte, PubInputChunkSize)))
+	circuit := PackBytesWrapper{
+		Data:   BytesToFrontVariable(digest[:]),
+		Packed: BigIntsToFrontVariable(packed),
+	}
+	assignment := PackBytesWrapper{
+		Data:   BytesToFrontVariable(digest[:]),
+		Packed: BigIntsToFrontVariable(packed),
+	}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
@@ -0,0 +1,73 @@
+package dkim
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// SelectorCapacity is the maximum byte length of the s= selector in the key name mode.
+const SelectorCapacity = 64
+
+// signatureSelectorPattern matches the relaxed canonical DKIM-Signature header trimmed after "b=",
+// the group captures the value of the s= tag, see signatureDomainPattern.
+const signatureSelectorPattern = `dkim-signature:(?:[^;]*;)* ?s ?= ?([!-:<-~]+) ?(?:;[^\r\n]*)?`
+
+// signatureSelectorRegex is the compiled signatureSelectorPattern.
+var signatureSelectorRegex = mustCompileRegex(signatureSelectorPattern)
+
+// ExtractSignatureSelector parses the s= selector of the trimmed DKIM-Signature header slice and returns it lowercased,
+// padded with trailing zeros to capacity bytes. captureMask marks the selector bytes, see SignatureSelectorMask.
+func (c *SliceApi) ExtractSignatureSelector(trimmedHeader PaddingSlice, captureMask []frontend.Variable, capacity int) ([]frontend.Variable, error) {
+	selector, err := c.signatureTagBytes(signatureSelectorRegex, trimmedHeader, captureMask)
+	if err != nil {
+		return nil, err
+	}
+	return c.packCaptured(selector, c.nonZeroBits(selector), capacity, false), nil
+}
+
+// SignatureSelector returns the lowercased s= selector of the DKIM signature of the email.
+func SignatureSelector(message string) (string, error) {
+	trimmedHeader, err := signatureTrimmedHeader(message)
+	if err != nil {
+		return "", err
+	}
+	return signatureSelector([]byte(trimmedHeader))
+}
+
+// signatureSelector parses the s= selector of the canonical trimmed DKIM-Signature header like the circuit.
+func signatureSelector(trimmedHeader []byte) (string, error) {
+	selector, ok := signatureTag(signatureSelectorRegex, trimmedHeader)
+	if !ok {
+		return "", errors.New("no selector found in the DKIM signature")
+	}
+	return strings.ToLower(string(selector)), nil
+}
+
+// SignatureSelectorMask returns the capture mask witness of the trimmed DKIM-Signature header padded to length bytes,
+// see ExtractSignatureSelector.
+func SignatureSelectorMask(trimmedHeader []byte, length int) ([]frontend.Variable, error) {
+	mask, _, err := signatureSelectorRegex.CaptureMask(trimmedHeader, length)
+	return mask, err
+}
+
+// KeyNameCommitment computes the public KeyName output of the circuit, which names the DNS record
+// selector._domainkey.domain of the DKIM key. The lowercased domain and selector are zero padded
+// to DomainCapacity and SelectorCapacity bytes and committed with the commitment hash h,
+// so contracts can check (domain, selector, keyHash) against a registry of the DKIM keys.
+func KeyNameCommitment(h CommitmentHash, domain, selector string) ([]*big.Int, error) {
+	domain, selector = strings.ToLower(domain), strings.ToLower(selector)
+	if len(domain) > DomainCapacity {
+		return nil, fmt.Errorf("signature domain %s is longer than %d bytes", domain, DomainCapacity)
+	}
+	if len(selector) > SelectorCapacity {
+		return nil, fmt.Errorf("signature selector %s is longer than %d bytes", selector, SelectorCapacity)
+	}
+	paddedDomain, paddedSelector := make([]byte, DomainCapacity), make([]byte, SelectorCapacity)
+	copy(paddedDomain, domain)
+	copy(paddedSelector, selector)
+	return CommitPublicInputs(h, paddedDomain, paddedSelector)
+}
@@ -0,0 +1,82 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type SelectorWrapper struct {
+	TrimmedHeader PaddingSlice
+	Mask          []frontend.Variable
+	Selector      []frontend.Variable `gnark:",public"`
+}
+
+func (c *SelectorWrapper) Define(api frontend.API) error {
+	sliceApi := NewSliceApi(api)
+	selector, err := sliceApi.ExtractSignatureSelector(c.TrimmedHeader, c.Mask, len(c.Selector))
+	if err != nil {
+		return err
+	}
+	for i := range c.Selector {
+		api.AssertIsEqual(c.Selector[i], selector[i])
+	}
+	return nil
+}
+
+func TestExtractSignatureSelector(t *testing.T) {
+	assert := test.NewAssert(t)
+	length, capacity := 80, 16
+	trimmedHeader := []byte("dkim-signature:v=1; ss=x; d=foxmail.com; s=S201512; bh=abc=; b=")
+	mask, err := SignatureSelectorMask(trimmedHeader, length)
+	assert.NoError(err)
+	circuit := SelectorWrapper{
+		TrimmedHeader: BytesToFixPadding(nil, false, length),
+		Mask:          make([]frontend.Variable, length),
+		Selector:      make([]frontend.Variable, capacity),
+	}
+	selector := make([]byte, capacity)
+	copy(selector, "s201512")
+	assignment := SelectorWrapper{
+		TrimmedHeader: BytesToFixPadding(trimmedHeader, false, length),
+		Mask:          mask,
+		Selector:      BytesToFrontVariable(selector),
+	}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// The ss= tag can not be taken as the selector.
+	copy(selector, "x\x00\x00\x00\x00\x00\x00")
+	assignment.Selector = BytesToFrontVariable(selector)
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestSignatureSelector(t *testing.T) {
+	assert := test.NewAssert(t)
+	selector, err := SignatureSelector(GmailTestData)
+	assert.NoError(err)
+	assert.Equal("20230601", selector)
+	selector, err = SignatureSelector(FoxmailTestData)
+	assert.NoError(err)
+	assert.Equal("s201512", selector)
+}
+
+func TestKeyName(t *testing.T) {
+	assert := test.NewAssert(t)
+	verifier, assignment := foxmailAssignment(assert, WithKeyName())
+	keyName, err := KeyNameCommitment(CommitmentSHA256, "FoxMail.com", "s201512")
+	assert.NoError(err)
+	assert.Equal(BigIntsToFrontVariable(keyName), assignment.KeyName)
+	err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// The key name of another selector does not match the signature.
+	other, err := KeyNameCommitment(CommitmentSHA256, "foxmail.com", "s201513")
+	assert.NoError(err)
+	assignment.KeyName = BigIntsToFrontVariable(other)
+	err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+	_, err = KeyNameCommitment(CommitmentSHA256, "foxmail.com", string(make([]byte, SelectorCapacity+1)))
+	assert.Error(err)
+}
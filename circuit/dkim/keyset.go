@@ -0,0 +1,107 @@
+package dkim
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// KeyPath is the Merkle inclusion proof of the public key hash in the key set mode.
+type KeyPath struct {
+	// Index is the leaf index, its bits select the side of each sibling from the leaf up.
+	Index    frontend.Variable
+	Siblings []frontend.Variable
+}
+
+// KeySet is a Merkle tree of allowlisted DKIM public key hashes.
+// Nodes are hashed with an algebraic commitment hash, unused leaves are 0.
+type KeySet struct {
+	hash   CommitmentHash
+	levels [][]*big.Int // levels[0] are the leaves, the last level is the root
+}
+
+// NewKeySet builds the key set tree of the given depth from the public key hashes, see PublicKeyHash.
+func NewKeySet(h CommitmentHash, depth int, keyHashes []*big.Int) (*KeySet, error) {
+	if h == CommitmentSHA256 {
+		return nil, errors.New("key set requires an algebraic commitment hash")
+	}
+	if depth <= 0 || depth >= 32 {
+		return nil, fmt.Errorf("invalid key set depth %d", depth)
+	}
+	if len(keyHashes) > 1<<depth {
+		return nil, fmt.Errorf("%d keys exceed the key set capacity %d", len(keyHashes), 1<<depth)
+	}
+	leaves := make([]*big.Int, 1<<depth)
+	for i := range leaves {
+		leaves[i] = new(big.Int)
+	}
+	copy(leaves, keyHashes)
+	levels := [][]*big.Int{leaves}
+	for len(levels[len(levels)-1]) > 1 {
+		children := levels[len(levels)-1]
+		parents := make([]*big.Int, len(children)/2)
+		for i := range parents {
+			node, err := hashFieldElements(h, children[2*i], children[2*i+1])
+			if err != nil {
+				return nil, err
+			}
+			parents[i] = node
+		}
+		levels = append(levels, parents)
+	}
+	return &KeySet{hash: h, levels: levels}, nil
+}
+
+// Hash returns the hash function of the tree nodes.
+func (ks *KeySet) Hash() CommitmentHash {
+	return ks.hash
+}
+
+// Depth returns the depth of the tree.
+func (ks *KeySet) Depth() int {
+	return len(ks.levels) - 1
+}
+
+// Root returns the Merkle root, which is the public KeyRoot of the circuit.
+func (ks *KeySet) Root() *big.Int {
+	return ks.levels[len(ks.levels)-1][0]
+}
+
+// Path returns the inclusion proof of the public key hash, the siblings are ordered from the leaf up.
+func (ks *KeySet) Path(keyHash *big.Int) (int, []*big.Int, error) {
+	index := -1
+	for i, leaf := range ks.levels[0] {
+		if leaf.Cmp(keyHash) == 0 {
+			index = i
+			break
+		}
+	}
+	if index == -1 || keyHash.Sign() == 0 {
+		return 0, nil, errors.New("public key is not in the key set")
+	}
+	siblings := make([]*big.Int, ks.Depth())
+	for level := range siblings {
+		siblings[level] = ks.levels[level][(index>>level)^1]
+	}
+	return index, siblings, nil
+}
+
+// verifyKeyPath checks inside the circuit that the leaf is in the key set tree with the root.
+func verifyKeyPath(api frontend.API, h CommitmentHash, leaf frontend.Variable, path KeyPath, root frontend.Variable) error {
+	indexBits := api.ToBinary(path.Index, len(path.Siblings))
+	node := leaf
+	for level, sibling := range path.Siblings {
+		hasher, err := newFieldHasher(api, h)
+		if err != nil {
+			return err
+		}
+		left := api.Select(indexBits[level], sibling, node)
+		right := api.Select(indexBits[level], node, sibling)
+		hasher.Write(left, right)
+		node = hasher.Sum()
+	}
+	api.AssertIsEqual(node, root)
+	return nil
+}
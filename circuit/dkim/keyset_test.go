@@ -0,0 +1,85 @@
+package dkim
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type KeyPathWrapper struct {
+	Leaf frontend.Variable
+	Path KeyPath
+	Root frontend.Variable `gnark:",public"`
+	Hash CommitmentHash    `gnark:"-"`
+}
+
+func (c *KeyPathWrapper) Define(api frontend.API) error {
+	return verifyKeyPath(api, c.Hash, c.Leaf, c.Path, c.Root)
+}
+
+func TestKeySetPath(t *testing.T) {
+	assert := test.NewAssert(t)
+	keyHashes := []*big.Int{big.NewInt(11), big.NewInt(22), big.NewInt(33)}
+	for _, h := range []CommitmentHash{CommitmentPoseidon2, CommitmentMiMC} {
+		keySet, err := NewKeySet(h, 3, keyHashes)
+		assert.NoError(err)
+		index, siblings, err := keySet.Path(keyHashes[2])
+		assert.NoError(err)
+		assert.Equal(2, index)
+		assert.Equal(3, len(siblings))
+		circuit := KeyPathWrapper{
+			Path: KeyPath{Siblings: make([]frontend.Variable, 3)},
+			Hash: h,
+		}
+		assignment := KeyPathWrapper{
+			Leaf: keyHashes[2],
+			Path: KeyPath{Index: index, Siblings: BigIntsToFrontVariable(siblings)},
+			Root: keySet.Root(),
+		}
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		// Another leaf does not open to the root with this path.
+		assignment.Leaf = keyHashes[1]
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.Error(err)
+	}
+	// Unused leaves are not members.
+	keySet, err := NewKeySet(CommitmentPoseidon2, 3, keyHashes)
+	assert.NoError(err)
+	_, _, err = keySet.Path(big.NewInt(0))
+	assert.Error(err)
+	_, err = NewKeySet(CommitmentSHA256, 3, keyHashes)
+	assert.Error(err)
+	_, err = NewKeySet(CommitmentPoseidon2, 1, keyHashes)
+	assert.Error(err)
+}
+
+func TestCustomDKIMVerifierKeySet(t *testing.T) {
+	assert := test.NewAssert(t)
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	_, err = GetCustomDKIMVerifierWrapper("foxmail", WithKeySet(4))
+	assert.Error(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithCommitmentHash(CommitmentPoseidon2), WithKeySet(4))
+	assert.NoError(err)
+	keyHash, err := verifier.PublicKeyHash(txtRecords[0])
+	assert.NoError(err)
+	keySet, err := NewKeySet(CommitmentPoseidon2, 4, []*big.Int{big.NewInt(1), keyHash[0]})
+	assert.NoError(err)
+	_, err = verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.Error(err)
+	assignment, err := verifier.NewKeySetAssignment(FoxmailTestData, txtRecords[0], keySet)
+	assert.NoError(err)
+	wrapper := assignment.(*CustomDKIMVerifierWrapper[Mod1e1024])
+	assert.Equal(0, len(wrapper.PubKeyHash))
+	assert.Equal([]frontend.Variable{keySet.Root()}, wrapper.KeyRoot)
+	assert.Equal(1, wrapper.KeyPath.Index)
+	// Keys outside of the key set cannot be assigned.
+	otherSet, err := NewKeySet(CommitmentPoseidon2, 4, []*big.Int{big.NewInt(1)})
+	assert.NoError(err)
+	_, err = verifier.NewKeySetAssignment(FoxmailTestData, txtRecords[0], otherSet)
+	assert.Error(err)
+}
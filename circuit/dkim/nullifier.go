@@ -0,0 +1,66 @@
+package dkim
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/doubiliu/zk-email/algorithm"
+)
+
+// nullifier computes the nullifier of the signature bytes inside the circuit, it is the commitment of the signature
+// folded into one field element. The RSA verification bounds the signature by N, so every email has one nullifier.
+func nullifier(api frontend.API, h CommitmentHash, sig []frontend.Variable) (frontend.Variable, error) {
+	digest, err := commitPublicInputs(api, h, sig)
+	if err != nil {
+		return nil, err
+	}
+	result := frontend.Variable(0)
+	shift := new(big.Int).Lsh(big.NewInt(1), 8*PubInputChunkSize)
+	for _, element := range digest {
+		result = api.Add(api.Mul(result, shift), element)
+	}
+	return result, nil
+}
+
+// Nullifier computes the Nullifier output of the circuit from the DKIM signature outside the circuit.
+// The signature is left padded to the byte length of a keyBits RSA modulus, a SHA-256 digest is reduced modulo
+// the BN254 scalar field.
+func Nullifier(h CommitmentHash, signature []byte, keyBits int) (*big.Int, error) {
+	width := (keyBits + 7) / 8
+	if len(signature) > width {
+		return nil, errors.New("signature size is too big")
+	}
+	sigContent := make([]byte, width)
+	copy(sigContent[width-len(signature):], signature)
+	digest, err := CommitPublicInputs(h, sigContent)
+	if err != nil {
+		return nil, err
+	}
+	result := new(big.Int)
+	for _, element := range digest {
+		result.Lsh(result, 8*PubInputChunkSize).Add(result, element)
+	}
+	return result.Mod(result, fr.Modulus()), nil
+}
+
+// EmailNullifier computes the Nullifier output of the circuit from the DKIM signature of the email.
+// An Ed25519 signature always takes its 64 bytes, keyBits is then ignored.
+func EmailNullifier(message string, h CommitmentHash, keyBits int) (*big.Int, error) {
+	email := algorithm.ParseEmail(message)
+	for _, header := range email.Headers() {
+		if algorithm.IsSignatureHeader(header) {
+			signature, err := algorithm.ParseSignature(header)
+			if err != nil {
+				return nil, err
+			}
+			if signature.Algo().KeyType() == "ed25519" {
+				keyBits = ed25519.SignatureSize * 8
+			}
+			return Nullifier(h, signature.Signature(), keyBits)
+		}
+	}
+	return nil, errors.New("no DKIM header found")
+}
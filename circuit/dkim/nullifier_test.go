@@ -0,0 +1,60 @@
+package dkim
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type NullifierWrapper struct {
+	Sig       []frontend.Variable
+	Nullifier frontend.Variable `gnark:",public"`
+	Hash      CommitmentHash    `gnark:"-"`
+}
+
+func (c *NullifierWrapper) Define(api frontend.API) error {
+	sigNullifier, err := nullifier(api, c.Hash, c.Sig)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(c.Nullifier, sigNullifier)
+	return nil
+}
+
+func TestNullifierCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	sig := []byte("a signature of sixteen or more bytes")
+	for _, h := range []CommitmentHash{CommitmentSHA256, CommitmentPoseidon2, CommitmentMiMC} {
+		expected, err := Nullifier(h, sig, len(sig)*8)
+		assert.NoError(err)
+		circuit := NullifierWrapper{Sig: make([]frontend.Variable, len(sig)), Hash: h}
+		assignment := NullifierWrapper{Sig: BytesToFrontVariable(sig), Nullifier: expected}
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		assignment.Sig = BytesToFrontVariable([]byte("A signature of sixteen or more bytes"))
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.Error(err)
+	}
+}
+
+func TestEmailNullifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	nullifiers := make(map[CommitmentHash]*big.Int)
+	for _, h := range []CommitmentHash{CommitmentSHA256, CommitmentPoseidon2} {
+		verifier, assignment := foxmailAssignment(assert, WithCommitmentHash(h))
+		expected, err := EmailNullifier(FoxmailTestData, h, verifier.KeyBits())
+		assert.NoError(err)
+		assert.Equal(expected, assignment.Nullifier)
+		nullifiers[h] = expected
+		err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		// A tampered nullifier does not match the signature.
+		assignment.Nullifier = new(big.Int).Add(expected, big.NewInt(1))
+		err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+		assert.Error(err)
+	}
+	assert.NotEqual(nullifiers[CommitmentSHA256], nullifiers[CommitmentPoseidon2])
+}
@@ -0,0 +1,239 @@
+package dkim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/doubiliu/zk-email/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Provider is the definition of a mail provider, the mail type of its circuit is the Name.
+// It is loaded from a JSON or YAML provider file by Registry.Load, and sets either a header sample or capacities.
+type Provider struct {
+	// Name is the mail type of the provider, it must not end with DomainMailTypeSuffix.
+	Name string `yaml:"name"`
+	// Header is the sample of the signed headers, see MailTemplate.Header. Line breaks may be LF or CRLF.
+	Header string `yaml:"header,omitempty"`
+	// HeaderFile is the file of the header sample instead of Header, relative to the provider file.
+	HeaderFile string `yaml:"headerFile,omitempty"`
+	// Capacities bound the circuit slices instead of a header sample, see UniversalTemplate.
+	Capacities *Capacities `yaml:"capacities,omitempty"`
+	// Canonicalization, when not empty, is the c= canonicalization of the provider's signatures,
+	// see WithCanonicalization.
+	Canonicalization string `yaml:"canonicalization,omitempty"`
+	// Domain, when not empty, is the d= domain of the provider's signatures, see WithSignatureDomain.
+	Domain string `yaml:"domain,omitempty"`
+	// KeyBits is the RSA modulus size of the provider's DKIM key, see MailTemplate.KeyBits.
+	KeyBits int `yaml:"keyBits,omitempty"`
+}
+
+// Template returns the mail template of the provider.
+func (p *Provider) Template() (MailTemplate, error) {
+	if p.Name == "" || strings.HasSuffix(p.Name, DomainMailTypeSuffix) {
+		return MailTemplate{}, fmt.Errorf("invalid provider name %q", p.Name)
+	}
+	if p.HeaderFile != "" {
+		return MailTemplate{}, fmt.Errorf("provider %s: header file %s is not loaded", p.Name, p.HeaderFile)
+	}
+	var template MailTemplate
+	switch {
+	case p.Header != "" && p.Capacities == nil:
+		header := utils.FixupNewlines(strings.ReplaceAll(p.Header, "\r\n", "\n"))
+		if _, err := emailSignature(header); err != nil {
+			return MailTemplate{}, fmt.Errorf("provider %s: %w", p.Name, err)
+		}
+		template = MailTemplate{Header: header, KeyBits: p.KeyBits}
+	case p.Header == "" && p.Capacities != nil:
+		if err := p.Capacities.check(); err != nil {
+			return MailTemplate{}, fmt.Errorf("provider %s: %w", p.Name, err)
+		}
+		template = UniversalTemplate(p.KeyBits, *p.Capacities)
+	default:
+		return MailTemplate{}, fmt.Errorf("provider %s must set either a header sample or capacities", p.Name)
+	}
+	if p.Canonicalization != "" {
+		template.Options = append(template.Options, WithCanonicalization(p.Canonicalization))
+	}
+	if p.Domain != "" {
+		template.Options = append(template.Options, WithSignatureDomain(p.Domain))
+	}
+	return template, nil
+}
+
+// LoadProvider reads the provider file, JSON being a subset of YAML both are accepted.
+// The header file of the provider is read too.
+func LoadProvider(path string) (Provider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Provider{}, err
+	}
+	var p Provider
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil {
+		return Provider{}, fmt.Errorf("provider file %s: %w", path, err)
+	}
+	if p.HeaderFile != "" {
+		if p.Header != "" {
+			return Provider{}, fmt.Errorf("provider file %s sets both header and headerFile", path)
+		}
+		header, err := os.ReadFile(filepath.Join(filepath.Dir(path), p.HeaderFile))
+		if err != nil {
+			return Provider{}, err
+		}
+		p.Header, p.HeaderFile = string(header), ""
+	}
+	return p, nil
+}
+
+// Registry maps the mail types to their providers. Registering is not safe for concurrent use.
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry returns a registry of the providers.
+func NewRegistry(providers ...Provider) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider)}
+	for _, p := range providers {
+		if err := r.Register(p); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds the provider to the registry, replacing the provider of the same name.
+func (r *Registry) Register(p Provider) error {
+	if _, err := p.Template(); err != nil {
+		return err
+	}
+	r.providers[p.Name] = p
+	return nil
+}
+
+// Load registers the provider file of the path, or every .json, .yaml and .yml file of the directory of the path.
+func (r *Registry) Load(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		p, err := LoadProvider(path)
+		if err != nil {
+			return err
+		}
+		return r.Register(p)
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	loaded := make(map[string]string)
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".json", ".yaml", ".yml":
+			if entry.IsDir() {
+				continue
+			}
+		default:
+			continue
+		}
+		file := filepath.Join(path, entry.Name())
+		p, err := LoadProvider(file)
+		if err != nil {
+			return err
+		}
+		if other, found := loaded[p.Name]; found {
+			return fmt.Errorf("provider %s is defined by both %s and %s", p.Name, other, file)
+		}
+		loaded[p.Name] = file
+		if err := r.Register(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clone returns a copy of the registry, so that providers can be added without changing the registry.
+func (r *Registry) Clone() *Registry {
+	providers := make(map[string]Provider, len(r.providers))
+	for name, p := range r.providers {
+		providers[name] = p
+	}
+	return &Registry{providers: providers}
+}
+
+// Provider returns the provider of the name.
+func (r *Registry) Provider(name string) (Provider, bool) {
+	p, found := r.providers[name]
+	return p, found
+}
+
+// Names returns the sorted names of the providers.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Template returns the mail template of the mail type, a provider name with the optional DomainMailTypeSuffix.
+func (r *Registry) Template(mailType string) (MailTemplate, error) {
+	name, domainOnly := strings.CutSuffix(mailType, DomainMailTypeSuffix)
+	p, found := r.providers[name]
+	if !found {
+		return MailTemplate{}, errors.New("unknown mail type")
+	}
+	template, err := p.Template()
+	if err != nil {
+		return MailTemplate{}, err
+	}
+	if domainOnly {
+		template = DomainTemplate(template)
+	}
+	return template, nil
+}
+
+// Verifier returns the DKIM verifier circuit template of the mail type, see NewCustomDKIMVerifierWrapper.
+func (r *Registry) Verifier(mailType string, opts ...Option) (DKIMVerifier, error) {
+	template, err := r.Template(mailType)
+	if err != nil {
+		return nil, err
+	}
+	return NewCustomDKIMVerifierWrapper(template, opts...)
+}
+
+// builtinProviders are the providers of the DefaultRegistry.
+var builtinProviders = []Provider{
+	{Name: "gmail", Header: GmailTemplate, KeyBits: 2048},
+	{Name: "outlook", Header: OutlookTemplate, KeyBits: 2048},
+	{Name: "foxmail", Header: FoxmailTemplate, KeyBits: 1024},
+	{Name: "icloud", Header: ICloudTemplate, KeyBits: 2048},
+	{Name: "ngd", Header: NGDTemplate, KeyBits: 2048},
+	{Name: "yahoo", Header: YahooTemplate, KeyBits: 2048},
+	{Name: "proton", Header: ProtonTemplate, KeyBits: 2048},
+	{Name: "qq", Header: QQTemplate, KeyBits: 1024},
+	{Name: "163", Header: NetEaseTemplate, KeyBits: 1024},
+	{Name: "zoho", Header: ZohoTemplate, KeyBits: 1024},
+	{Name: UniversalMailType, Capacities: &DefaultCapacities, KeyBits: 2048},
+}
+
+// DefaultRegistry holds the built-in providers, GetCustomDKIMVerifierWrapper builds its mail types.
+var DefaultRegistry = mustNewRegistry(builtinProviders...)
+
+// mustNewRegistry is like NewRegistry but panics if a provider is invalid.
+func mustNewRegistry(providers ...Provider) *Registry {
+	r, err := NewRegistry(providers...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
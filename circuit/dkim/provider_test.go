@@ -0,0 +1,128 @@
+package dkim
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.Equal([]string{"163", "foxmail", "gmail", "icloud", "ngd", "outlook", "proton", "qq", UniversalMailType, "yahoo", "zoho"}, DefaultRegistry.Names())
+	for _, name := range DefaultRegistry.Names() {
+		_, err := GetCustomDKIMVerifierWrapper(name)
+		assert.NoError(err, name)
+	}
+	verifier, err := GetCustomDKIMVerifierWrapper("ngd")
+	assert.NoError(err)
+	_, err = verifier.NewAssignment(NGDTestData, lookupTestRecord(assert, NGDTestData))
+	assert.NoError(err)
+	_, err = GetCustomDKIMVerifierWrapper("aol")
+	assert.Error(err)
+}
+
+func TestRegistryLoad(t *testing.T) {
+	assert := test.NewAssert(t)
+	dir := t.TempDir()
+	files := map[string]string{
+		"foxmail.yaml": "name: qq-foxmail\nheaderFile: foxmail.eml\ncanonicalization: relaxed\ndomain: FoxMail.com\nkeyBits: 1024\n",
+		"foxmail.eml":  FoxmailTemplate,
+		"corp.json":    `{"name": "corp", "capacities": {"prefix": 256, "revealed": 96, "suffix": 256, "sigPrefix": 192, "sigSuffix": 64}, "keyBits": 2048}`,
+		"README.md":    "not a provider",
+	}
+	for name, content := range files {
+		assert.NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	registry := DefaultRegistry.Clone()
+	assert.NoError(registry.Load(dir))
+	assert.Equal(13, len(registry.Names()))
+	// The built-in registry is not changed by the clone.
+	_, found := DefaultRegistry.Provider("corp")
+	assert.False(found)
+	p, found := registry.Provider("qq-foxmail")
+	assert.True(found)
+	assert.Equal("", p.HeaderFile)
+	template, err := registry.Template("qq-foxmail")
+	assert.NoError(err)
+	assert.Equal(FoxmailTemplate, template.Header)
+	verifier, err := registry.Verifier("corp-domain")
+	assert.NoError(err)
+	assert.Equal(2048, verifier.KeyBits())
+	assert.Equal(96, len(verifier.(*CustomDKIMVerifierWrapper[Mod1e2048]).Header.SpecifyData[0].Slice))
+	assert.True(verifier.(*CustomDKIMVerifierWrapper[Mod1e2048]).Config.SenderDomainOnly)
+	// A provider file of a path is loaded alone.
+	registry, err = NewRegistry()
+	assert.NoError(err)
+	assert.NoError(registry.Load(filepath.Join(dir, "corp.json")))
+	assert.Equal([]string{"corp"}, registry.Names())
+}
+
+func TestRegistryInvalidProvider(t *testing.T) {
+	assert := test.NewAssert(t)
+	capacities := &Capacities{Prefix: 64, Revealed: 64, Suffix: 64, SigPrefix: 64, SigSuffix: 64}
+	for _, p := range []Provider{
+		{Name: "", Header: FoxmailTemplate, KeyBits: 1024},
+		{Name: "foxmail-domain", Header: FoxmailTemplate, KeyBits: 1024},
+		{Name: "foxmail", KeyBits: 1024},
+		{Name: "foxmail", Header: FoxmailTemplate, Capacities: capacities, KeyBits: 1024},
+		{Name: "foxmail", Header: "From: x\r\n", KeyBits: 1024},
+		{Name: "foxmail", HeaderFile: "foxmail.eml", KeyBits: 1024},
+		{Name: "corp", Capacities: &Capacities{Prefix: 64}, KeyBits: 2048},
+	} {
+		_, err := NewRegistry(p)
+		assert.Error(err, p.Name)
+	}
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"unknown.yaml": "name: corp\nkeybits: 2048\ncapacities: {prefix: 1, revealed: 1, suffix: 1, sigPrefix: 1, sigSuffix: 1}\n",
+		"both.yaml":    "name: corp\nheader: x\nheaderFile: corp.eml\n",
+	} {
+		path := filepath.Join(dir, name)
+		assert.NoError(os.WriteFile(path, []byte(content), 0o644))
+		_, err := LoadProvider(path)
+		assert.Error(err, name)
+	}
+	// Two files of a directory can not define the same provider.
+	dir = t.TempDir()
+	for _, name := range []string{"a.json", "b.json"} {
+		content := `{"name": "corp", "capacities": {"prefix": 1, "revealed": 1, "suffix": 1, "sigPrefix": 1, "sigSuffix": 1}, "keyBits": 2048}`
+		assert.NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	registry, err := NewRegistry()
+	assert.NoError(err)
+	assert.Error(registry.Load(dir))
+}
+
+func TestWithSignatureDomain(t *testing.T) {
+	assert := test.NewAssert(t)
+	txtRecord := lookupTestRecord(assert, FoxmailTestData)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithSignatureDomain("FoxMail.com"), WithCanonicalization("relaxed"))
+	assert.NoError(err)
+	assert.Equal("foxmail.com", verifier.(*CustomDKIMVerifierWrapper[Mod1e1024]).Config.SignatureDomain)
+	assert.Equal("relaxed/relaxed", verifier.(*CustomDKIMVerifierWrapper[Mod1e1024]).Config.Canonicalization)
+	assignment, err := verifier.NewAssignment(FoxmailTestData, txtRecord)
+	assert.NoError(err)
+	err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// The circuit of another domain rejects the signature of foxmail.com.
+	other, err := GetCustomDKIMVerifierWrapper("foxmail", WithSignatureDomain("qq.com"))
+	assert.NoError(err)
+	_, err = other.NewAssignment(FoxmailTestData, txtRecord)
+	assert.Error(err)
+	err = test.IsSolved(other, assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+	// The canonicalization must match the template and the emails.
+	_, err = GetCustomDKIMVerifierWrapper("foxmail", WithCanonicalization("simple/simple"))
+	assert.Error(err)
+	_, err = GetCustomDKIMVerifierWrapper("foxmail", WithCanonicalization("strict"))
+	assert.Error(err)
+	universal, err := NewCustomDKIMVerifierWrapper(UniversalTemplate(1024, DefaultCapacities), WithCanonicalization("simple/relaxed"))
+	assert.NoError(err)
+	_, err = universal.NewAssignment(FoxmailTestData, txtRecord)
+	assert.Error(err)
+	_, err = GetCustomDKIMVerifierWrapper("foxmail", WithSignatureDomain("foxmail com"))
+	assert.Error(err)
+}
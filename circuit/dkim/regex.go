@@ -0,0 +1,355 @@
+package dkim
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// maxRegexStates bounds the DFA size, the transition table has 512 entries per state.
+const maxRegexStates = 1024
+
+// Regex is a regular expression compiled into a DFA over bytes, it always matches the whole data.
+// The first capturing group is the marked group whose bytes can be revealed, the other groups only group.
+// Each DFA symbol is a byte with a capture bit, so the prover chooses which bytes are captured
+// and the DFA checks that the choice is a valid parse; patterns should not be ambiguous about the group.
+// A tagged regex has no marked group, instead every DFA state tells the group of the byte leading to it.
+type Regex struct {
+	pattern string
+	start   int
+	accept  []bool
+	next    [][512]int // next[state][capture<<8|byte]
+	tags    []int      // tags[state] is the group of the last byte in a tagged regex, 0 outside the groups
+}
+
+// regexThread is an NFA thread of the compiled program, inGroup is set between the marks of the first group,
+// group is the open group of a tagged regex.
+type regexThread struct {
+	pc      uint32
+	inGroup bool
+	group   int
+}
+
+// CompileRegex compiles the pattern (RE2 syntax) into a DFA. Bytes are matched as the code points 0-255,
+// so the pattern should be ASCII. ^ and $ are no-ops since the whole data is matched.
+func CompileRegex(pattern string) (*Regex, error) {
+	return compileRegex(pattern, false)
+}
+
+// CompileTaggedRegex compiles the pattern like CompileRegex into a tagged regex, see MatchTaggedRegex.
+// The capturing groups must not nest, and every byte of a match must belong to the same group
+// in all the parses, otherwise the pattern is rejected.
+func CompileTaggedRegex(pattern string) (*Regex, error) {
+	return compileRegex(pattern, true)
+}
+
+// compileRegex compiles the pattern with the first group marked, or tagged with all the groups.
+func compileRegex(pattern string, tagged bool) (*Regex, error) {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	re := &Regex{pattern: pattern}
+	index := make(map[string]int)
+	queue := make([][]regexThread, 0)
+	addState := func(threads []regexThread, tag int) (int, error) {
+		key := fmt.Sprintf("%s#%d", threadsKey(threads), tag)
+		if state, ok := index[key]; ok {
+			return state, nil
+		}
+		if len(re.accept) == maxRegexStates {
+			return 0, fmt.Errorf("regex %q needs more than %d DFA states", pattern, maxRegexStates)
+		}
+		state := len(re.accept)
+		index[key] = state
+		accept := false
+		for _, thread := range threads {
+			if prog.Inst[thread.pc].Op == syntax.InstMatch {
+				accept = true
+			}
+		}
+		re.accept = append(re.accept, accept)
+		re.next = append(re.next, [512]int{})
+		re.tags = append(re.tags, tag)
+		queue = append(queue, threads)
+		return state, nil
+	}
+	startThreads, err := regexClosure(prog, []regexThread{{pc: uint32(prog.Start)}}, tagged)
+	if err != nil {
+		return nil, err
+	}
+	re.start, err = addState(startThreads, 0)
+	if err != nil {
+		return nil, err
+	}
+	for state := 0; state < len(queue); state++ {
+		for symbol := 0; symbol < 512; symbol++ {
+			inGroup, b := symbol>>8 == 1, rune(symbol&0xff)
+			stepped := make([]regexThread, 0)
+			tag := 0
+			for _, thread := range queue[state] {
+				inst := prog.Inst[thread.pc]
+				if thread.inGroup != inGroup {
+					continue
+				}
+				switch inst.Op {
+				case syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+					if inst.MatchRune(b) {
+						if len(stepped) != 0 && thread.group != tag {
+							return nil, fmt.Errorf("regex %q is ambiguous about the group of %q", pattern, b)
+						}
+						tag = thread.group
+						stepped = append(stepped, regexThread{pc: inst.Out, inGroup: thread.inGroup, group: thread.group})
+					}
+				}
+			}
+			closed, err := regexClosure(prog, stepped, tagged)
+			if err != nil {
+				return nil, err
+			}
+			next, err := addState(closed, tag)
+			if err != nil {
+				return nil, err
+			}
+			re.next[state][symbol] = next
+		}
+	}
+	return re, nil
+}
+
+// regexClosure follows the empty transitions of the threads and keeps the consuming and matching ones.
+// The groups of a tagged regex are opened and closed, otherwise only the first group is marked.
+func regexClosure(prog *syntax.Prog, threads []regexThread, tagged bool) ([]regexThread, error) {
+	seen := make(map[regexThread]bool)
+	result := make([]regexThread, 0)
+	stack := slices.Clone(threads)
+	for len(stack) > 0 {
+		thread := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[thread] {
+			continue
+		}
+		seen[thread] = true
+		inst := prog.Inst[thread.pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			stack = append(stack, regexThread{pc: inst.Arg, inGroup: thread.inGroup, group: thread.group}, regexThread{pc: inst.Out, inGroup: thread.inGroup, group: thread.group})
+		case syntax.InstCapture:
+			inGroup, group := thread.inGroup, thread.group
+			// Arg 2k and 2k+1 are the marks of the capturing group k.
+			switch {
+			case tagged && inst.Arg%2 == 0:
+				if group != 0 {
+					return nil, errors.New("tagged regex groups must not nest")
+				}
+				group = int(inst.Arg / 2)
+			case tagged:
+				group = 0
+			case inst.Arg == 2:
+				inGroup = true
+			case inst.Arg == 3:
+				inGroup = false
+			}
+			stack = append(stack, regexThread{pc: inst.Out, inGroup: inGroup, group: group})
+		case syntax.InstNop:
+			stack = append(stack, regexThread{pc: inst.Out, inGroup: thread.inGroup, group: thread.group})
+		case syntax.InstEmptyWidth:
+			if syntax.EmptyOp(inst.Arg)&^(syntax.EmptyBeginText|syntax.EmptyEndText) != 0 {
+				return nil, errors.New("regex assertions other than ^ and $ are not supported")
+			}
+			stack = append(stack, regexThread{pc: inst.Out, inGroup: thread.inGroup, group: thread.group})
+		case syntax.InstFail:
+		default:
+			result = append(result, thread)
+		}
+	}
+	return result, nil
+}
+
+// threadsKey identifies a DFA state by its set of threads.
+func threadsKey(threads []regexThread) string {
+	keys := make([]string, len(threads))
+	for i, thread := range threads {
+		keys[i] = fmt.Sprintf("%d:%t:%d", thread.pc, thread.inGroup, thread.group)
+	}
+	slices.Sort(keys)
+	return strings.Join(keys, ",")
+}
+
+// String returns the source pattern.
+func (re *Regex) String() string {
+	return re.pattern
+}
+
+// Capture matches the whole data outside the circuit and returns the capture bit of every byte,
+// the marked group captures greedily. It returns false if the data does not match.
+func (re *Regex) Capture(data []byte) ([]bool, bool) {
+	// canAccept[i][state] tells whether the rest of the data from i can be accepted from the state.
+	canAccept := make([][]bool, len(data)+1)
+	canAccept[len(data)] = slices.Clone(re.accept)
+	for i := len(data) - 1; i >= 0; i-- {
+		canAccept[i] = make([]bool, len(re.accept))
+		for state := range re.accept {
+			canAccept[i][state] = canAccept[i+1][re.next[state][0x100|int(data[i])]] || canAccept[i+1][re.next[state][int(data[i])]]
+		}
+	}
+	if !canAccept[0][re.start] {
+		return nil, false
+	}
+	mask := make([]bool, len(data))
+	state := re.start
+	for i, b := range data {
+		if next := re.next[state][0x100|int(b)]; canAccept[i+1][next] {
+			mask[i], state = true, next
+		} else {
+			state = re.next[state][int(b)]
+		}
+	}
+	return mask, true
+}
+
+// CaptureMask returns the capture mask witness of MatchRegex for data laid out by BytesToFixPadding with the
+// length, together with the captured bytes.
+func (re *Regex) CaptureMask(data []byte, length int) ([]frontend.Variable, []byte, error) {
+	if len(data) > length {
+		return nil, nil, errors.New("input length exceeds max length")
+	}
+	mask, ok := re.Capture(data)
+	if !ok {
+		return nil, nil, fmt.Errorf("data does not match the regex %q", re.pattern)
+	}
+	result := make([]frontend.Variable, length)
+	captured := make([]byte, 0)
+	for i := range result {
+		result[i] = 0
+	}
+	for i := range mask {
+		if mask[i] {
+			result[length-len(data)+i] = 1
+			captured = append(captured, data[i])
+		}
+	}
+	return result, captured, nil
+}
+
+// Tags matches the whole data with the tagged regex outside the circuit and returns the group of every byte,
+// 0 outside the groups. It returns false if the data does not match.
+func (re *Regex) Tags(data []byte) ([]int, bool) {
+	tags := make([]int, len(data))
+	state := re.start
+	for i, b := range data {
+		state = re.next[state][b]
+		tags[i] = re.tags[state]
+	}
+	return tags, re.accept[state]
+}
+
+// MatchRegex runs the DFA of re over the data of the slice, the padding is skipped.
+// It returns 1 if the whole data matches and 0 otherwise, together with the slice bytes in the marked group
+// in place and 0 elsewhere. captureMask has one bit per slice byte in the slice order, nil captures nothing.
+func (c *SliceApi) MatchRegex(re *Regex, s PaddingSlice, captureMask []frontend.Variable) (frontend.Variable, []frontend.Variable, error) {
+	states, captured, err := c.runRegex(re, s, captureMask)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.acceptRegex(re, states), captured, nil
+}
+
+// MatchTaggedRegex runs the DFA of the tagged re over the data of the slice, the padding is skipped.
+// It returns 1 if the whole data matches and 0 otherwise, together with the group of every slice byte,
+// 0 outside the groups and in the padding.
+func (c *SliceApi) MatchTaggedRegex(re *Regex, s PaddingSlice) (frontend.Variable, []frontend.Variable, error) {
+	states, _, err := c.runRegex(re, s, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	table := logderivlookup.New(c.api)
+	for _, tag := range re.tags {
+		table.Insert(tag)
+	}
+	tags := table.Lookup(states...)
+	if s.IsLittleEndian {
+		slices.Reverse(tags)
+	}
+	return c.acceptRegex(re, states), tags, nil
+}
+
+// acceptRegex returns 1 if the last of the DFA states is accepting and 0 otherwise.
+func (c *SliceApi) acceptRegex(re *Regex, states []frontend.Variable) frontend.Variable {
+	acceptFlags := make([]frontend.Variable, len(re.accept))
+	for i, accept := range re.accept {
+		acceptFlags[i] = 0
+		if accept {
+			acceptFlags[i] = 1
+		}
+	}
+	state := frontend.Variable(re.start)
+	if len(states) != 0 {
+		state = states[len(states)-1]
+	}
+	return selector.Mux(c.api, state, acceptFlags...)
+}
+
+// runRegex runs the DFA of re over the big-endian data of the slice and returns the state after every byte,
+// the padding keeps the start state, together with the captured bytes in the slice order like MatchRegex.
+func (c *SliceApi) runRegex(re *Regex, s PaddingSlice, captureMask []frontend.Variable) ([]frontend.Variable, []frontend.Variable, error) {
+	api := c.api
+	if captureMask != nil && len(captureMask) != len(s.Slice) {
+		return nil, nil, errors.New("capture mask length does not match the slice")
+	}
+	bigEndian := s.Clone()
+	mask := slices.Clone(captureMask)
+	if bigEndian.IsLittleEndian {
+		bigEndian = bigEndian.Reverse(api)
+		slices.Reverse(mask)
+	}
+	table := logderivlookup.New(api)
+	for state := range re.next {
+		for symbol := range re.next[state] {
+			table.Insert(re.next[state][symbol])
+		}
+	}
+	// Bytes must be range checked, otherwise they alias the rows of other states.
+	rangeChecker := rangecheck.New(api)
+	active := selector.StepMask(api, len(bigEndian.Slice), api.Add(bigEndian.Padding, 1), 0, 1)
+	captured := make([]frontend.Variable, len(bigEndian.Slice))
+	states := make([]frontend.Variable, len(bigEndian.Slice))
+	state := frontend.Variable(re.start)
+	for i, b := range bigEndian.Slice {
+		rangeChecker.Check(b, 8)
+		bit := frontend.Variable(0)
+		if mask != nil {
+			bit = mask[i]
+			api.AssertIsBoolean(bit)
+		}
+		next := table.Lookup(api.Add(api.Mul(state, 512), api.Mul(bit, 256), b))[0]
+		state = api.Select(active[i], next, state)
+		states[i] = state
+		captured[i] = api.Mul(b, api.Mul(bit, active[i]))
+	}
+	if s.IsLittleEndian {
+		slices.Reverse(captured)
+	}
+	return states, captured, nil
+}
+
+// AssertMatchRegex asserts that the whole data of the slice matches re and returns the captured bytes like MatchRegex.
+func (c *SliceApi) AssertMatchRegex(re *Regex, s PaddingSlice, captureMask []frontend.Variable) ([]frontend.Variable, error) {
+	matched, captured, err := c.MatchRegex(re, s, captureMask)
+	if err != nil {
+		return nil, err
+	}
+	c.api.AssertIsEqual(matched, 1)
+	return captured, nil
+}
@@ -0,0 +1,110 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type RegexWrapper struct {
+	Data     PaddingSlice
+	Mask     []frontend.Variable
+	Captured []frontend.Variable
+	Regex    *Regex `gnark:"-"`
+}
+
+func (c *RegexWrapper) Define(api frontend.API) error {
+	sliceApi := NewSliceApi(api)
+	captured, err := sliceApi.AssertMatchRegex(c.Regex, c.Data, c.Mask)
+	if err != nil {
+		return err
+	}
+	for i := range c.Captured {
+		api.AssertIsEqual(c.Captured[i], captured[i])
+	}
+	return nil
+}
+
+func TestCompileRegex(t *testing.T) {
+	assert := test.NewAssert(t)
+	re, err := CompileRegex(`from:[^<]*<([a-z.]+@[a-z.]+)>\r\n`)
+	assert.NoError(err)
+	data := []byte("from:Alice <alice@example.com>\r\n")
+	mask, ok := re.Capture(data)
+	assert.True(ok)
+	captured := make([]byte, 0)
+	for i := range mask {
+		if mask[i] {
+			captured = append(captured, data[i])
+		}
+	}
+	assert.Equal("alice@example.com", string(captured))
+	_, ok = re.Capture([]byte("from:alice@example.com\r\n"))
+	assert.False(ok)
+	_, err = CompileRegex(`\bfrom`)
+	assert.Error(err)
+}
+
+func TestCompileTaggedRegex(t *testing.T) {
+	assert := test.NewAssert(t)
+	re, err := CompileTaggedRegex(`([0-9]+):([0-9]+)(?: (am|pm))?`)
+	assert.NoError(err)
+	tags, ok := re.Tags([]byte("12:30 pm"))
+	assert.True(ok)
+	assert.Equal([]int{1, 1, 0, 2, 2, 0, 3, 3}, tags)
+	_, ok = re.Tags([]byte("12:30 "))
+	assert.False(ok)
+	_, err = CompileTaggedRegex(`(a)*(a)b`)
+	assert.Error(err)
+	_, err = CompileTaggedRegex(`((a)b)`)
+	assert.Error(err)
+}
+
+func TestMatchRegex(t *testing.T) {
+	assert := test.NewAssert(t)
+	re, err := CompileRegex(`from:[^<]*<([a-z.]+@[a-z.]+)>\r\n`)
+	assert.NoError(err)
+	data := []byte("from:Bob <bob@qq.com>\r\n")
+	length := len(data) + 8
+	mask, captured, err := re.CaptureMask(data, length)
+	assert.NoError(err)
+	assert.Equal("bob@qq.com", string(captured))
+	expected := make([]byte, length)
+	for i := range data {
+		if mask[length-len(data)+i] == 1 {
+			expected[length-len(data)+i] = data[i]
+		}
+	}
+	circuit := RegexWrapper{
+		Data:     BytesToFixPadding(data, false, length),
+		Mask:     make([]frontend.Variable, length),
+		Captured: make([]frontend.Variable, length),
+		Regex:    re,
+	}
+	assignment := RegexWrapper{
+		Data:     BytesToFixPadding(data, false, length),
+		Mask:     mask,
+		Captured: BytesToFrontVariable(expected),
+	}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// Capturing the display name is not a valid parse.
+	badMask := make([]frontend.Variable, length)
+	copy(badMask, mask)
+	badMask[length-len(data)+5] = 1
+	assignment.Mask = badMask
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+	// Data without the angle brackets is rejected.
+	other := []byte("from:bob@qq.com\r\n")
+	assignment.Data = BytesToFixPadding(other, false, length)
+	assignment.Mask = make([]frontend.Variable, length)
+	for i := range assignment.Mask {
+		assignment.Mask[i] = 0
+	}
+	assignment.Captured = BytesToFrontVariable(make([]byte, length))
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
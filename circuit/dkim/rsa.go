@@ -1,7 +1,10 @@
 package dkim
 
 import (
+	"crypto"
 	"errors"
+	"fmt"
+	"math/big"
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/math/emulated"
@@ -11,21 +14,45 @@ func NewRSA[T emulated.FieldParams](api frontend.API) RSA[T] {
 	return RSA[T]{api: api}
 }
 
+// NewFixedExponentRSA returns an RSA verifier whose public exponent is the circuit constant e,
+// so that the exponentiation becomes a fixed chain of modular squarings and multiplications.
+func NewFixedExponentRSA[T emulated.FieldParams](api frontend.API, e int) RSA[T] {
+	return RSA[T]{api: api, exponent: e}
+}
+
 type PublicKey[T emulated.FieldParams] struct {
 	N emulated.Element[T] // Modulus
 	E emulated.Element[T]
 }
 
 type RSA[T emulated.FieldParams] struct {
-	api frontend.API
+	api      frontend.API
+	exponent int // 0 means the exponent is read from the public key
+}
+
+// digestInfoPrefixes are the DER encoded DigestInfo prefixes of EMSA-PKCS1-v1_5, see RFC 8017 section 9.2,
+// of the hash functions newBinaryHasher supports.
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
 }
 
-func (rsa *RSA[T]) VerifyPkcs1v15(pubKey *PublicKey[T], sign, hashed []frontend.Variable) error {
+// VerifyPkcs1v15 verifies the RSASSA-PKCS1-v1_5 signature of the digest hashed computed with the hash function.
+func (rsa *RSA[T]) VerifyPkcs1v15(pubKey *PublicKey[T], hash crypto.Hash, sign, hashed []frontend.Variable) error {
+	prefix, ok := digestInfoPrefixes[hash]
+	if !ok {
+		return fmt.Errorf("unsupported hash function %v", hash)
+	}
+	if len(hashed) != hash.Size() {
+		return errors.New("hashed length does not match the hash function")
+	}
 	em, err := rsa.encrypt(pubKey, sign)
 	if err != nil {
 		return err
 	}
-	expected, err := rsa.pkcs1v15ConstructEM(rsa.api, hashed, len(sign))
+	expected, err := rsa.pkcs1v15ConstructEM(rsa.api, prefix, hashed, len(sign))
 	if err != nil {
 		return err
 	}
@@ -47,13 +74,34 @@ func (rsa *RSA[T]) encrypt(pub *PublicKey[T], sign []frontend.Variable) ([]front
 	// Ensure the bitlength of pub.N is not larger than sign, here checks the value directly.
 	p := f.FromBits(BytesToBits(rsa.api, sign)...)
 	f.AssertIsLessOrEqual(p, &pub.N)
+	if rsa.exponent != 0 {
+		// The exponent is a constant, pub.E must carry the same value.
+		f.AssertIsEqual(&pub.E, f.NewElement(rsa.exponent))
+		return f.ToBits(rsa.modExpConst(f, p, &pub.N)), nil
+	}
 	// Compute p = sign^e mod n.
 	em := f.ToBits(f.ModExp(p, &pub.E, &pub.N))
 	return em, nil
 }
 
-func (rsa *RSA[T]) pkcs1v15ConstructEM(api frontend.API, hashed []frontend.Variable, k int) ([]frontend.Variable, error) {
-	prefix := []frontend.Variable{frontend.Variable(0x30), frontend.Variable(0x31), frontend.Variable(0x30), frontend.Variable(0x0d), frontend.Variable(0x06), frontend.Variable(0x09), frontend.Variable(0x60), frontend.Variable(0x86), frontend.Variable(0x48), frontend.Variable(0x01), frontend.Variable(0x65), frontend.Variable(0x03), frontend.Variable(0x04), frontend.Variable(0x02), frontend.Variable(0x01), frontend.Variable(0x05), frontend.Variable(0x00), frontend.Variable(0x04), frontend.Variable(0x20)}
+// modExpConst computes base^exponent mod n by square-and-multiply over the bits of the constant exponent,
+// e.g. 16 squarings and one multiplication for 65537.
+func (rsa *RSA[T]) modExpConst(f *emulated.Field[T], base, n *emulated.Element[T]) *emulated.Element[T] {
+	e := big.NewInt(int64(rsa.exponent))
+	result := base
+	for i := e.BitLen() - 2; i >= 0; i-- {
+		result = f.ModMul(result, result, n)
+		if e.Bit(i) == 1 {
+			result = f.ModMul(result, base, n)
+		}
+	}
+	return result
+}
+
+func (rsa *RSA[T]) pkcs1v15ConstructEM(api frontend.API, prefix []byte, hashed []frontend.Variable, k int) ([]frontend.Variable, error) {
+	if k < len(prefix)+len(hashed)+11 {
+		return nil, errors.New("signature too short for the digest")
+	}
 	// EM = 0x00 || 0x01 || PS || 0x00 || T
 	em := make([]frontend.Variable, k)
 	for i := 0; i < k; i++ {
@@ -63,7 +111,7 @@ func (rsa *RSA[T]) pkcs1v15ConstructEM(api frontend.API, hashed []frontend.Varia
 	for i := 2; i < k-len(prefix)-len(hashed)-1; i++ {
 		em[i] = 0xff
 	}
-	copy(em[k-len(prefix)-len(hashed):], prefix)
+	copy(em[k-len(prefix)-len(hashed):], BytesToFrontVariable(prefix))
 	copy(em[k-len(hashed):], hashed)
 	return BytesToBits(api, em), nil
 }
@@ -0,0 +1,47 @@
+package dkim
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Mod1e1024 provides type parametrization for emulated arithmetic over 1024-bit RSA moduli:
+//   - limbs: 16
+//   - limb width: 64 bits
+//
+// The modulus for type parametrisation is 2^1024-1, it is only used with variable-modulus operations.
+type Mod1e1024 struct{}
+
+func (Mod1e1024) NbLimbs() uint     { return 16 }
+func (Mod1e1024) BitsPerLimb() uint { return 64 }
+func (Mod1e1024) IsPrime() bool     { return false }
+func (Mod1e1024) Modulus() *big.Int {
+	return mod1eBits(1024)
+}
+
+// Mod1e2048 provides type parametrization for emulated arithmetic over 2048-bit RSA moduli:
+//   - limbs: 32
+//   - limb width: 64 bits
+//
+// The modulus for type parametrisation is 2^2048-1, it is only used with variable-modulus operations.
+type Mod1e2048 struct{}
+
+func (Mod1e2048) NbLimbs() uint     { return 32 }
+func (Mod1e2048) BitsPerLimb() uint { return 64 }
+func (Mod1e2048) IsPrime() bool     { return false }
+func (Mod1e2048) Modulus() *big.Int {
+	return mod1eBits(2048)
+}
+
+// mod1eBits returns 2^bits-1.
+func mod1eBits(bits uint) *big.Int {
+	val := new(big.Int).Lsh(big.NewInt(1), bits)
+	return val.Sub(val, big.NewInt(1))
+}
+
+// keyBytes returns the byte length of the RSA modulus supported by the field parameters.
+func keyBytes[T emulated.FieldParams]() int {
+	var params T
+	return (params.Modulus().BitLen() + 7) / 8
+}
@@ -2,7 +2,11 @@ package dkim
 
 import (
 	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/base64"
 	"fmt"
 	"math/big"
@@ -71,16 +75,122 @@ func TestRSACircuit(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestRSACircuitFixedExponent(t *testing.T) {
+	assert := test.NewAssert(t)
+	prvkey, _, err, structPubKey := algorithm.GenRsaKey(1024)
+	assert.NoError(err)
+	data := []byte("foo")
+	sig, err := algorithm.RsaSign(prvkey, crypto.SHA256, data)
+	assert.NoError(err)
+	hashSum := sha256.Sum256(data)
+	circuit := RSAWrapper[Mod1e1024]{
+		PublicKey: &PublicKey[Mod1e1024]{
+			N: emulated.ValueOf[Mod1e1024](structPubKey.N),
+			E: emulated.ValueOf[Mod1e1024](structPubKey.E),
+		},
+		Sign:          BytesToFrontVariable(sig),
+		Hashed:        BytesToFrontVariable(hashSum[:]),
+		FixedExponent: DefaultExponent,
+	}
+	assignment := RSAWrapper[Mod1e1024]{
+		PublicKey: &PublicKey[Mod1e1024]{
+			N: emulated.ValueOf[Mod1e1024](structPubKey.N),
+			E: emulated.ValueOf[Mod1e1024](structPubKey.E),
+		},
+		Sign:   BytesToFrontVariable(sig),
+		Hashed: BytesToFrontVariable(hashSum[:]),
+	}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// The exponent of the key must match the circuit constant.
+	assignment.PublicKey.E = emulated.ValueOf[Mod1e1024](3)
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestRSACircuitSha1(t *testing.T) {
+	assert := test.NewAssert(t)
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(err)
+	hashSum := sha1.Sum([]byte("foo"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA1, hashSum[:])
+	assert.NoError(err)
+	circuit := RSAWrapper[Mod1e1024]{
+		PublicKey: &PublicKey[Mod1e1024]{
+			N: emulated.ValueOf[Mod1e1024](privateKey.N),
+			E: emulated.ValueOf[Mod1e1024](privateKey.E),
+		},
+		Sign:          BytesToFrontVariable(sig),
+		Hashed:        BytesToFrontVariable(hashSum[:]),
+		FixedExponent: DefaultExponent,
+		Hash:          crypto.SHA1,
+	}
+	assignment := RSAWrapper[Mod1e1024]{
+		PublicKey: &PublicKey[Mod1e1024]{
+			N: emulated.ValueOf[Mod1e1024](privateKey.N),
+			E: emulated.ValueOf[Mod1e1024](privateKey.E),
+		},
+		Sign:   BytesToFrontVariable(sig),
+		Hashed: BytesToFrontVariable(hashSum[:]),
+	}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestRSACircuitSha384(t *testing.T) {
+	assert := test.NewAssert(t)
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(err)
+	hashSum := sha512.Sum384([]byte("foo"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA384, hashSum[:])
+	assert.NoError(err)
+	circuit := RSAWrapper[Mod1e1024]{
+		PublicKey: &PublicKey[Mod1e1024]{
+			N: emulated.ValueOf[Mod1e1024](privateKey.N),
+			E: emulated.ValueOf[Mod1e1024](privateKey.E),
+		},
+		Sign:          BytesToFrontVariable(sig),
+		Hashed:        BytesToFrontVariable(hashSum[:]),
+		FixedExponent: DefaultExponent,
+		Hash:          crypto.SHA384,
+	}
+	assignment := RSAWrapper[Mod1e1024]{
+		PublicKey: &PublicKey[Mod1e1024]{
+			N: emulated.ValueOf[Mod1e1024](privateKey.N),
+			E: emulated.ValueOf[Mod1e1024](privateKey.E),
+		},
+		Sign:   BytesToFrontVariable(sig),
+		Hashed: BytesToFrontVariable(hashSum[:]),
+	}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// The SHA-256 DigestInfo does not verify the SHA-384 signature.
+	sha256Sum := sha256.Sum256([]byte("foo"))
+	circuit.Hash, circuit.Hashed = crypto.SHA256, BytesToFrontVariable(sha256Sum[:])
+	assignment.Hashed = BytesToFrontVariable(sha256Sum[:])
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
 type RSAWrapper[T emulated.FieldParams] struct {
-	PublicKey *PublicKey[T]
-	Sign      []frontend.Variable
-	Hashed    []frontend.Variable
+	PublicKey     *PublicKey[T]
+	Sign          []frontend.Variable
+	Hashed        []frontend.Variable
+	FixedExponent int         `gnark:"-"`
+	Hash          crypto.Hash `gnark:"-"`
 }
 
 // Define declares the circuit's constraints.
 func (c *RSAWrapper[T]) Define(api frontend.API) error {
 	rsa := NewRSA[T](api)
-	err := rsa.VerifyPkcs1v15(c.PublicKey, c.Sign, c.Hashed)
+	if c.FixedExponent != 0 {
+		rsa = NewFixedExponentRSA[T](api, c.FixedExponent)
+	}
+	hash := c.Hash
+	if hash == 0 {
+		hash = crypto.SHA256
+	}
+	err := rsa.VerifyPkcs1v15(c.PublicKey, hash, c.Sign, c.Hashed)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,251 @@
+package dkim
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// senderPattern matches the relaxed canonical From header, "from:Name <addr-spec>\r\n" or "from:addr-spec\r\n".
+// The address is printable ASCII without spaces, '<', '>' and a second '@', the group captures it.
+const senderPattern = `from:(?:[^<\r\n]*<)?([!-;=?A-~]+@[!-;=?A-~]+)>?\r\n`
+
+// SaltSize is the byte length of the sender salt in the salted sender mode.
+const SaltSize = 32
+
+// senderRegex is the compiled senderPattern.
+var senderRegex = mustCompileRegex(senderPattern)
+
+// mustCompileRegex compiles the constant pattern and panics on error.
+func mustCompileRegex(pattern string) *Regex {
+	re, err := CompileRegex(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// ExtractSender parses the address of the From header slice and returns it with the domain lowercased,
+// padded with trailing zeros to capacity bytes. captureMask marks the address bytes, see SenderMask.
+func (c *SliceApi) ExtractSender(from PaddingSlice, captureMask []frontend.Variable, capacity int) ([]frontend.Variable, error) {
+	return c.extractSender(from, captureMask, capacity, false)
+}
+
+// ExtractSenderDomain is ExtractSender returning only the lowercased domain after the '@' of the address.
+func (c *SliceApi) ExtractSenderDomain(from PaddingSlice, captureMask []frontend.Variable, capacity int) ([]frontend.Variable, error) {
+	return c.extractSender(from, captureMask, capacity, true)
+}
+
+// extractSender returns the address or the domain of the From header slice.
+func (c *SliceApi) extractSender(from PaddingSlice, captureMask []frontend.Variable, capacity int, domainOnly bool) ([]frontend.Variable, error) {
+	address, domainBits, err := c.senderBytes(from, captureMask)
+	if err != nil {
+		return nil, err
+	}
+	if domainOnly {
+		return c.packCaptured(c.selectBytes(address, domainBits), domainBits, capacity, false), nil
+	}
+	return c.packCaptured(address, c.nonZeroBits(address), capacity, false), nil
+}
+
+// senderBytes returns the big-endian bytes of the From header slice with the address in place,
+// its domain lowercased, and 0 elsewhere, together with the bits selecting the domain after the '@'.
+func (c *SliceApi) senderBytes(from PaddingSlice, captureMask []frontend.Variable) ([]frontend.Variable, []frontend.Variable, error) {
+	api := c.api
+	captured, err := c.AssertMatchRegex(senderRegex, from, captureMask)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from.IsLittleEndian {
+		captured = slices.Clone(captured)
+		slices.Reverse(captured)
+	}
+	// The captured bytes are never 0.
+	lower := newLowerTable(api)
+	seenAt := frontend.Variable(0)
+	address := make([]frontend.Variable, len(captured))
+	domainBits := make([]frontend.Variable, len(captured))
+	for i, b := range captured {
+		// The domain follows the '@', which is excluded.
+		domainBits[i] = api.Mul(api.Sub(1, api.IsZero(b)), seenAt)
+		seenAt = api.Or(seenAt, api.IsZero(api.Sub(b, '@')))
+		address[i] = api.Select(seenAt, lower.Lookup(b)[0], b)
+	}
+	return address, domainBits, nil
+}
+
+// nonZeroBits returns the bits selecting the non zero values.
+func (c *SliceApi) nonZeroBits(values []frontend.Variable) []frontend.Variable {
+	bits := make([]frontend.Variable, len(values))
+	for i := range values {
+		bits[i] = c.api.Sub(1, c.api.IsZero(values[i]))
+	}
+	return bits
+}
+
+// selectBytes returns the values where the bits are 1 and 0 elsewhere.
+func (c *SliceApi) selectBytes(values, bits []frontend.Variable) []frontend.Variable {
+	result := make([]frontend.Variable, len(values))
+	for i := range values {
+		result[i] = c.api.Mul(values[i], bits[i])
+	}
+	return result
+}
+
+// packCaptured moves the contiguous values selected by the bits to the front of a capacity slice padded with zeros,
+// or to its back if alignRight is set. The values must be 0 where the bits are 0.
+func (c *SliceApi) packCaptured(values, bits []frontend.Variable, capacity int, alignRight bool) []frontend.Variable {
+	api := c.api
+	values, bits = slices.Clone(values), slices.Clone(bits)
+	if alignRight {
+		slices.Reverse(values)
+		slices.Reverse(bits)
+	}
+	seen := frontend.Variable(0)
+	start, length := frontend.Variable(0), frontend.Variable(0)
+	for i := range bits {
+		seen = api.Or(seen, bits[i])
+		start = api.Add(start, api.Sub(1, seen))
+		length = api.Add(length, bits[i])
+	}
+	api.AssertIsLessOrEqual(length, capacity)
+	result := c.LeftShift(values, start)
+	for len(result) < capacity {
+		result = append(result, 0)
+	}
+	result = result[:capacity]
+	if alignRight {
+		slices.Reverse(result)
+	}
+	return result
+}
+
+// SenderAddress returns the address of the signed From header of the email with the domain lowercased.
+func SenderAddress(message string) (string, error) {
+	canonHeader, err := signedHeader(message, "from")
+	if err != nil {
+		return "", err
+	}
+	return senderAddress(canonHeader)
+}
+
+// senderAddress parses the address of the canonical From header like the circuit.
+func senderAddress(canonHeader []byte) (string, error) {
+	mask, ok := senderRegex.Capture(canonHeader)
+	if !ok {
+		return "", errors.New("no address found in the from header")
+	}
+	address := make([]byte, 0)
+	for i := range mask {
+		if mask[i] {
+			address = append(address, canonHeader[i])
+		}
+	}
+	return NormalizeAddress(string(address))
+}
+
+// NormalizeAddress checks the addr-spec and lowercases its domain, the local part is case sensitive.
+func NormalizeAddress(address string) (string, error) {
+	at := strings.IndexByte(address, '@')
+	if at <= 0 || at == len(address)-1 || strings.Count(address, "@") != 1 {
+		return "", fmt.Errorf("invalid email address %q", address)
+	}
+	for i := 0; i < len(address); i++ {
+		if address[i] <= ' ' || address[i] > '~' || address[i] == '<' || address[i] == '>' {
+			return "", fmt.Errorf("invalid email address %q", address)
+		}
+	}
+	return address[:at] + strings.ToLower(address[at:]), nil
+}
+
+// SenderMask returns the capture mask witness of the From header padded to length bytes, see ExtractSender.
+func SenderMask(canonHeader []byte, length int) ([]frontend.Variable, error) {
+	mask, _, err := senderRegex.CaptureMask(canonHeader, length)
+	return mask, err
+}
+
+// SenderCommitment computes the public Sender output of the circuit from the email address alone.
+// The normalized address is zero padded to capacity bytes and committed with the commitment hash h.
+func SenderCommitment(h CommitmentHash, address string, capacity int) ([]*big.Int, error) {
+	address, err := NormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return senderCommitment(h, address, capacity, nil)
+}
+
+// SaltedSenderCommitment computes the public Sender output of the circuit in the salted sender mode,
+// the salt of SaltSize bytes is committed after the padded address. Once the user reveals the salt,
+// anyone can check the address against the commitment.
+func SaltedSenderCommitment(h CommitmentHash, address string, capacity int, salt []byte) ([]*big.Int, error) {
+	if len(salt) != SaltSize {
+		return nil, fmt.Errorf("sender salt must be %d bytes", SaltSize)
+	}
+	address, err := NormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return senderCommitment(h, address, capacity, salt)
+}
+
+// senderCommitment commits to the normalized sender zero padded to capacity bytes, followed by the salt if any.
+func senderCommitment(h CommitmentHash, sender string, capacity int, salt []byte) ([]*big.Int, error) {
+	if len(sender) > capacity {
+		return nil, errors.New("email sender size is too big")
+	}
+	padded := make([]byte, capacity)
+	copy(padded, sender)
+	return CommitPublicInputs(h, padded, salt)
+}
+
+// SenderDomain returns the lowercased domain of the address of the signed From header of the email.
+func SenderDomain(message string) (string, error) {
+	address, err := SenderAddress(message)
+	if err != nil {
+		return "", err
+	}
+	return address[strings.IndexByte(address, '@')+1:], nil
+}
+
+// SenderDomainCommitment computes the public Sender output of the circuit in the domain only mode from the domain.
+// The lowercased domain is zero padded to capacity bytes and committed with the commitment hash h.
+func SenderDomainCommitment(h CommitmentHash, domain string, capacity int) ([]*big.Int, error) {
+	domain, err := normalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	return senderCommitment(h, domain, capacity, nil)
+}
+
+// SaltedSenderDomainCommitment computes the public Sender output of the circuit in the salted domain only mode.
+func SaltedSenderDomainCommitment(h CommitmentHash, domain string, capacity int, salt []byte) ([]*big.Int, error) {
+	if len(salt) != SaltSize {
+		return nil, fmt.Errorf("sender salt must be %d bytes", SaltSize)
+	}
+	domain, err := normalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	return senderCommitment(h, domain, capacity, salt)
+}
+
+// normalizeDomain checks that the domain is the domain of a valid address and lowercases it.
+func normalizeDomain(domain string) (string, error) {
+	address, err := NormalizeAddress("x@" + domain)
+	if err != nil {
+		return "", fmt.Errorf("invalid email domain %q", domain)
+	}
+	return address[2:], nil
+}
+
+// commitmentSize returns the number of field elements of a commitment output like Sender and KeyName.
+func commitmentSize(h CommitmentHash) int {
+	if h == CommitmentSHA256 {
+		return len(PackBytes(make([]byte, 32)))
+	}
+	return 1
+}
@@ -0,0 +1,176 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type SenderWrapper struct {
+	From       PaddingSlice
+	Mask       []frontend.Variable
+	Address    []frontend.Variable
+	DomainOnly bool `gnark:"-"`
+}
+
+func (c *SenderWrapper) Define(api frontend.API) error {
+	sliceApi := NewSliceApi(api)
+	extract := sliceApi.ExtractSender
+	if c.DomainOnly {
+		extract = sliceApi.ExtractSenderDomain
+	}
+	address, err := extract(c.From, c.Mask, len(c.Address))
+	if err != nil {
+		return err
+	}
+	for i := range c.Address {
+		api.AssertIsEqual(c.Address[i], address[i])
+	}
+	return nil
+}
+
+func TestExtractSender(t *testing.T) {
+	assert := test.NewAssert(t)
+	length := 48
+	address := []byte("Bob@qq.com\x00\x00\x00\x00\x00\x00")
+	circuit := SenderWrapper{
+		From:    BytesToFixPadding(nil, false, length),
+		Mask:    make([]frontend.Variable, length),
+		Address: make([]frontend.Variable, len(address)),
+	}
+	for _, from := range []string{"from:Bob <Bob@QQ.com>\r\n", "from:Bob@qq.COM\r\n"} {
+		mask, err := SenderMask([]byte(from), length)
+		assert.NoError(err)
+		assignment := SenderWrapper{
+			From:    BytesToFixPadding([]byte(from), false, length),
+			Mask:    mask,
+			Address: BytesToFrontVariable(address),
+		}
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+	// The display name can not be taken as the address.
+	from := []byte("from:a@b.c <Bob@qq.com>\r\n")
+	assignment := SenderWrapper{
+		From:    BytesToFixPadding(from, false, length),
+		Mask:    BytesToFrontVariable(make([]byte, length)),
+		Address: BytesToFrontVariable([]byte("a@b.c\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")),
+	}
+	for i := 0; i < 5; i++ {
+		assignment.Mask[length-len(from)+5+i] = 1
+	}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestExtractSenderDomain(t *testing.T) {
+	assert := test.NewAssert(t)
+	length := 48
+	circuit := SenderWrapper{
+		From:       BytesToFixPadding(nil, false, length),
+		Mask:       make([]frontend.Variable, length),
+		Address:    make([]frontend.Variable, 8),
+		DomainOnly: true,
+	}
+	from := []byte("from:a@b <Bob@QQ.com>\r\n")
+	mask, err := SenderMask(from, length)
+	assert.NoError(err)
+	assignment := SenderWrapper{
+		From:    BytesToFixPadding(from, false, length),
+		Mask:    mask,
+		Address: BytesToFrontVariable([]byte("qq.com\x00\x00")),
+	}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// The local part is not output.
+	assignment.Address = BytesToFrontVariable([]byte("@qq.com\x00"))
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestSenderCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	address, err := SenderAddress(FoxmailTestData)
+	assert.NoError(err)
+	assert.Equal("jinghui.liao@foxmail.com", address)
+	commitment, err := SenderCommitment(CommitmentPoseidon2, address, 64)
+	assert.NoError(err)
+	upper, err := SenderCommitment(CommitmentPoseidon2, "jinghui.liao@FOXMAIL.com", 64)
+	assert.NoError(err)
+	assert.Equal(commitment, upper)
+	_, err = SenderCommitment(CommitmentPoseidon2, "Jinghui <jinghui.liao@foxmail.com>", 64)
+	assert.Error(err)
+	_, err = SenderCommitment(CommitmentPoseidon2, address, 8)
+	assert.Error(err)
+	// The assignment commits to the address of the From header.
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	_, err = GetCustomDKIMVerifierWrapper("foxmail", WithRevealedHeaders("subject"), WithSenderAddress(64))
+	assert.Error(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithCommitmentHash(CommitmentPoseidon2), WithSenderAddress(64))
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.NoError(err)
+	assert.Equal(BigIntsToFrontVariable(commitment), assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).Sender)
+}
+
+func TestSenderDomainCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	domain, err := SenderDomain(FoxmailTestData)
+	assert.NoError(err)
+	assert.Equal("foxmail.com", domain)
+	commitment, err := SenderDomainCommitment(CommitmentPoseidon2, "FoxMail.com", DomainCapacity)
+	assert.NoError(err)
+	_, err = SenderDomainCommitment(CommitmentPoseidon2, "jinghui.liao@foxmail.com", DomainCapacity)
+	assert.Error(err)
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail"+DomainMailTypeSuffix, WithCommitmentHash(CommitmentPoseidon2))
+	assert.NoError(err)
+	assignment, err := verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.NoError(err)
+	assert.Equal(BigIntsToFrontVariable(commitment), assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).Sender)
+}
+
+func TestSaltedSenderCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	salt := make([]byte, SaltSize)
+	salt[0] = 1
+	commitment, err := SaltedSenderCommitment(CommitmentPoseidon2, "jinghui.liao@foxmail.com", 64, salt)
+	assert.NoError(err)
+	plain, err := SenderCommitment(CommitmentPoseidon2, "jinghui.liao@foxmail.com", 64)
+	assert.NoError(err)
+	assert.NotEqual(plain, commitment)
+	_, err = SaltedSenderCommitment(CommitmentPoseidon2, "jinghui.liao@foxmail.com", 64, salt[1:])
+	assert.Error(err)
+	txtRecords, err := client.LookupTxt("s201512._domainkey.foxmail.com.")
+	assert.NoError(err)
+	_, err = GetCustomDKIMVerifierWrapper("foxmail", WithSaltedSender())
+	assert.Error(err)
+	verifier, err := GetCustomDKIMVerifierWrapper("foxmail", WithCommitmentHash(CommitmentPoseidon2), WithSenderAddress(64), WithSaltedSender())
+	assert.NoError(err)
+	_, err = verifier.NewAssignment(FoxmailTestData, txtRecords[0])
+	assert.Error(err)
+	assignment, err := verifier.NewSaltedAssignment(FoxmailTestData, txtRecords[0], nil, salt)
+	assert.NoError(err)
+	assert.Equal(BigIntsToFrontVariable(commitment), assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).Sender)
+	assert.Equal(BytesToFrontVariable(salt), assignment.(*CustomDKIMVerifierWrapper[Mod1e1024]).SenderSalt)
+}
+
+func TestSenderHeaderBoundary(t *testing.T) {
+	assert := test.NewAssert(t)
+	// "Subject: hi from:victim@example.com" canonicalizes to "subject:hi from:victim@example.com\r\n",
+	// its tail matches the From header pattern and is aligned with d=example.com.
+	forged := "from:victim@example.com\r\n"
+	assignment := forgedAssignment(assert, ForgedTestData, forged, newVerifierConfig(WithSenderAddress(64), WithDomainAlignment(AlignmentRelaxed)))
+	mask, err := SenderMask([]byte(forged), len(forged))
+	assert.NoError(err)
+	assignment.SenderMask = mask
+	sender, err := SenderCommitment(assignment.Config.Commitment, "victim@example.com", 64)
+	assert.NoError(err)
+	assignment.Sender = BigIntsToFrontVariable(sender)
+	err = test.IsSolved(assignment, assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,106 @@
+package dkim
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+var sha1Seed = uints.NewU32Array([]uint32{
+	0x67452301, 0xEFCDAB89, 0x98BADCFE, 0x10325476, 0xC3D2E1F0,
+})
+
+var sha1K = uints.NewU32Array([]uint32{
+	0x5A827999, 0x6ED9EBA1, 0x8F1BBCDC, 0xCA62C1D6,
+})
+
+// sha1Digest computes SHA-1 inside the circuit, it is only meant for verifying legacy rsa-sha1 signatures.
+type sha1Digest struct {
+	uapi *uints.BinaryField[uints.U32]
+	in   []uints.U8
+}
+
+// NewSha1 returns an in-circuit SHA-1 hasher.
+func NewSha1(api frontend.API) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("initializing uints: %w", err)
+	}
+	return &sha1Digest{uapi: uapi}, nil
+}
+
+func (d *sha1Digest) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+func (d *sha1Digest) Size() int {
+	return 20
+}
+
+// Sum pads the input like SHA-2 (0x80, zeros and the 64-bit big-endian bit length) and runs the compression function.
+func (d *sha1Digest) Sum() []uints.U8 {
+	zeroPadLen := 55 - len(d.in)%64
+	if zeroPadLen < 0 {
+		zeroPadLen += 64
+	}
+	padded := make([]uints.U8, 0, len(d.in)+9+zeroPadLen)
+	padded = append(padded, d.in...)
+	padded = append(padded, uints.NewU8(0x80))
+	padded = append(padded, uints.NewU8Array(make([]uint8, zeroPadLen))...)
+	lenBuf := make([]uint8, 8)
+	binary.BigEndian.PutUint64(lenBuf, uint64(8*len(d.in)))
+	padded = append(padded, uints.NewU8Array(lenBuf)...)
+
+	var runningDigest [5]uints.U32
+	var buf [64]uints.U8
+	copy(runningDigest[:], sha1Seed)
+	for i := 0; i < len(padded)/64; i++ {
+		copy(buf[:], padded[i*64:(i+1)*64])
+		runningDigest = sha1Permute(d.uapi, runningDigest, buf)
+	}
+	result := make([]uints.U8, 0, d.Size())
+	for i := range runningDigest {
+		result = append(result, d.uapi.UnpackMSB(runningDigest[i])...)
+	}
+	return result
+}
+
+// sha1Permute is the SHA-1 compression function of one 64-byte block.
+func sha1Permute(uapi *uints.BinaryField[uints.U32], currentHash [5]uints.U32, p [64]uints.U8) [5]uints.U32 {
+	var w [80]uints.U32
+	for i := 0; i < 16; i++ {
+		w[i] = uapi.PackMSB(p[4*i], p[4*i+1], p[4*i+2], p[4*i+3])
+	}
+	for i := 16; i < 80; i++ {
+		w[i] = uapi.Lrot(uapi.Xor(w[i-3], w[i-8], w[i-14], w[i-16]), 1)
+	}
+	a, b, c, d, e := currentHash[0], currentHash[1], currentHash[2], currentHash[3], currentHash[4]
+	for i := 0; i < 80; i++ {
+		var f uints.U32
+		switch {
+		case i < 20:
+			f = uapi.Xor(uapi.And(b, c), uapi.And(uapi.Not(b), d))
+		case i < 40:
+			f = uapi.Xor(b, c, d)
+		case i < 60:
+			f = uapi.Xor(uapi.And(b, c), uapi.And(b, d), uapi.And(c, d))
+		default:
+			f = uapi.Xor(b, c, d)
+		}
+		t := uapi.Add(uapi.Lrot(a, 5), f, e, sha1K[i/20], w[i])
+		e = d
+		d = c
+		c = uapi.Lrot(b, 30)
+		b = a
+		a = t
+	}
+	currentHash[0] = uapi.Add(currentHash[0], a)
+	currentHash[1] = uapi.Add(currentHash[1], b)
+	currentHash[2] = uapi.Add(currentHash[2], c)
+	currentHash[3] = uapi.Add(currentHash[3], d)
+	currentHash[4] = uapi.Add(currentHash[4], e)
+	return currentHash
+}
@@ -0,0 +1,49 @@
+package dkim
+
+import (
+	"crypto"
+	"crypto/sha1"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type Sha1Wrapper struct {
+	Data       []frontend.Variable
+	ExpectHash []frontend.Variable
+}
+
+func (c *Sha1Wrapper) Define(api frontend.API) error {
+	digest, err := hashBytes(api, crypto.SHA1, c.Data)
+	if err != nil {
+		return err
+	}
+	for i := range c.ExpectHash {
+		api.AssertIsEqual(c.ExpectHash[i], digest[i])
+	}
+	return nil
+}
+
+func TestSha1(t *testing.T) {
+	assert := test.NewAssert(t)
+	// 70 bytes spans two blocks after padding.
+	data := []byte("DKIM-Signature: v=1; a=rsa-sha1; c=relaxed/relaxed; d=example.com; s=s")
+	hashSum := sha1.Sum(data)
+	circuit := Sha1Wrapper{
+		Data:       BytesToFrontVariable(data),
+		ExpectHash: BytesToFrontVariable(hashSum[:]),
+	}
+	assignment := Sha1Wrapper{
+		Data:       BytesToFrontVariable(data),
+		ExpectHash: BytesToFrontVariable(hashSum[:]),
+	}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// A wrong digest must not be accepted.
+	hashSum[0] ^= 1
+	assignment.ExpectHash = BytesToFrontVariable(hashSum[:])
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
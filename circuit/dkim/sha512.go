@@ -0,0 +1,148 @@
+package dkim
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+var sha512Seed = uints.NewU64Array([]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+})
+
+// sha384Seed is the initial hash value of SHA-384, which is SHA-512 with another seed and a truncated digest.
+var sha384Seed = uints.NewU64Array([]uint64{
+	0xcbbb9d5dc1059ed8, 0x629a292a367cd507, 0x9159015a3070dd17, 0x152fecd8f70e5939,
+	0x67332667ffc00b31, 0x8eb44a8768581511, 0xdb0c2e0d64f98fa7, 0x47b5481dbefa4fa4,
+})
+
+var sha512K = uints.NewU64Array([]uint64{
+	0x428a2f98d728ae22, 0x7137449123ef65cd, 0xb5c0fbcfec4d3b2f, 0xe9b5dba58189dbbc,
+	0x3956c25bf348b538, 0x59f111f1b605d019, 0x923f82a4af194f9b, 0xab1c5ed5da6d8118,
+	0xd807aa98a3030242, 0x12835b0145706fbe, 0x243185be4ee4b28c, 0x550c7dc3d5ffb4e2,
+	0x72be5d74f27b896f, 0x80deb1fe3b1696b1, 0x9bdc06a725c71235, 0xc19bf174cf692694,
+	0xe49b69c19ef14ad2, 0xefbe4786384f25e3, 0x0fc19dc68b8cd5b5, 0x240ca1cc77ac9c65,
+	0x2de92c6f592b0275, 0x4a7484aa6ea6e483, 0x5cb0a9dcbd41fbd4, 0x76f988da831153b5,
+	0x983e5152ee66dfab, 0xa831c66d2db43210, 0xb00327c898fb213f, 0xbf597fc7beef0ee4,
+	0xc6e00bf33da88fc2, 0xd5a79147930aa725, 0x06ca6351e003826f, 0x142929670a0e6e70,
+	0x27b70a8546d22ffc, 0x2e1b21385c26c926, 0x4d2c6dfc5ac42aed, 0x53380d139d95b3df,
+	0x650a73548baf63de, 0x766a0abb3c77b2a8, 0x81c2c92e47edaee6, 0x92722c851482353b,
+	0xa2bfe8a14cf10364, 0xa81a664bbc423001, 0xc24b8b70d0f89791, 0xc76c51a30654be30,
+	0xd192e819d6ef5218, 0xd69906245565a910, 0xf40e35855771202a, 0x106aa07032bbd1b8,
+	0x19a4c116b8d2d0c8, 0x1e376c085141ab53, 0x2748774cdf8eeb99, 0x34b0bcb5e19b48a8,
+	0x391c0cb3c5c95a63, 0x4ed8aa4ae3418acb, 0x5b9cca4f7763e373, 0x682e6ff3d6b2b8a3,
+	0x748f82ee5defb2fc, 0x78a5636f43172f60, 0x84c87814a1f0ab72, 0x8cc702081a6439ec,
+	0x90befffa23631e28, 0xa4506cebde82bde9, 0xbef9a3f7b2c67915, 0xc67178f2e372532b,
+	0xca273eceea26619c, 0xd186b8c721c0c207, 0xeada7dd6cde0eb1e, 0xf57d4f7fee6ed178,
+	0x06f067aa72176fba, 0x0a637dc5a2c898a6, 0x113f9804bef90dae, 0x1b710b35131c471b,
+	0x28db77f523047d84, 0x32caab7b40c72493, 0x3c9ebe0a15c9bebc, 0x431d67c49c100d4c,
+	0x4cc5d4becb3e42b6, 0x597f299cfc657e2a, 0x5fcb6fab3ad6faec, 0x6c44198c4a475817,
+})
+
+// sha512Digest computes SHA-512 inside the circuit for the challenge hash of Ed25519 signatures,
+// or SHA-384 for RSA signatures.
+type sha512Digest struct {
+	uapi *uints.BinaryField[uints.U64]
+	seed []uints.U64
+	size int
+	in   []uints.U8
+}
+
+// NewSha512 returns an in-circuit SHA-512 hasher.
+func NewSha512(api frontend.API) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, fmt.Errorf("initializing uints: %w", err)
+	}
+	return &sha512Digest{uapi: uapi, seed: sha512Seed, size: 64}, nil
+}
+
+// NewSha384 returns an in-circuit SHA-384 hasher.
+func NewSha384(api frontend.API) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, fmt.Errorf("initializing uints: %w", err)
+	}
+	return &sha512Digest{uapi: uapi, seed: sha384Seed, size: 48}, nil
+}
+
+func (d *sha512Digest) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+func (d *sha512Digest) Size() int {
+	return d.size
+}
+
+// Sum pads the input with 0x80, zeros and the 128-bit big-endian bit length and runs the compression function,
+// the digest is truncated to Size bytes.
+func (d *sha512Digest) Sum() []uints.U8 {
+	zeroPadLen := 111 - len(d.in)%128
+	if zeroPadLen < 0 {
+		zeroPadLen += 128
+	}
+	padded := make([]uints.U8, 0, len(d.in)+17+zeroPadLen)
+	padded = append(padded, d.in...)
+	padded = append(padded, uints.NewU8(0x80))
+	padded = append(padded, uints.NewU8Array(make([]uint8, zeroPadLen))...)
+	lenBuf := make([]uint8, 16)
+	binary.BigEndian.PutUint64(lenBuf[8:], uint64(8*len(d.in)))
+	padded = append(padded, uints.NewU8Array(lenBuf)...)
+
+	var runningDigest [8]uints.U64
+	var buf [128]uints.U8
+	copy(runningDigest[:], d.seed)
+	for i := 0; i < len(padded)/128; i++ {
+		copy(buf[:], padded[i*128:(i+1)*128])
+		runningDigest = sha512Permute(d.uapi, runningDigest, buf)
+	}
+	result := make([]uints.U8, 0, len(runningDigest)*8)
+	for i := range runningDigest {
+		result = append(result, d.uapi.UnpackMSB(runningDigest[i])...)
+	}
+	return result[:d.Size()]
+}
+
+// sha512Permute is the SHA-512 compression function of one 128-byte block, the rotations are to the right.
+func sha512Permute(uapi *uints.BinaryField[uints.U64], currentHash [8]uints.U64, p [128]uints.U8) [8]uints.U64 {
+	var w [80]uints.U64
+	for i := 0; i < 16; i++ {
+		w[i] = uapi.PackMSB(p[8*i], p[8*i+1], p[8*i+2], p[8*i+3], p[8*i+4], p[8*i+5], p[8*i+6], p[8*i+7])
+	}
+	for i := 16; i < 80; i++ {
+		s0 := uapi.Xor(uapi.Lrot(w[i-15], -1), uapi.Lrot(w[i-15], -8), uapi.Rshift(w[i-15], 7))
+		s1 := uapi.Xor(uapi.Lrot(w[i-2], -19), uapi.Lrot(w[i-2], -61), uapi.Rshift(w[i-2], 6))
+		w[i] = uapi.Add(w[i-16], s0, w[i-7], s1)
+	}
+	a, b, c, d := currentHash[0], currentHash[1], currentHash[2], currentHash[3]
+	e, f, g, h := currentHash[4], currentHash[5], currentHash[6], currentHash[7]
+	for i := 0; i < 80; i++ {
+		s1 := uapi.Xor(uapi.Lrot(e, -14), uapi.Lrot(e, -18), uapi.Lrot(e, -41))
+		ch := uapi.Xor(uapi.And(e, f), uapi.And(uapi.Not(e), g))
+		t1 := uapi.Add(h, s1, ch, sha512K[i], w[i])
+		s0 := uapi.Xor(uapi.Lrot(a, -28), uapi.Lrot(a, -34), uapi.Lrot(a, -39))
+		maj := uapi.Xor(uapi.And(a, b), uapi.And(a, c), uapi.And(b, c))
+		t2 := uapi.Add(s0, maj)
+		h = g
+		g = f
+		f = e
+		e = uapi.Add(d, t1)
+		d = c
+		c = b
+		b = a
+		a = uapi.Add(t1, t2)
+	}
+	currentHash[0] = uapi.Add(currentHash[0], a)
+	currentHash[1] = uapi.Add(currentHash[1], b)
+	currentHash[2] = uapi.Add(currentHash[2], c)
+	currentHash[3] = uapi.Add(currentHash[3], d)
+	currentHash[4] = uapi.Add(currentHash[4], e)
+	currentHash[5] = uapi.Add(currentHash[5], f)
+	currentHash[6] = uapi.Add(currentHash[6], g)
+	currentHash[7] = uapi.Add(currentHash[7], h)
+	return currentHash
+}
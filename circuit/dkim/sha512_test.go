@@ -0,0 +1,85 @@
+package dkim
+
+import (
+	"crypto"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type Sha512Wrapper struct {
+	Data       []frontend.Variable
+	ExpectHash []frontend.Variable
+	Hash       crypto.Hash `gnark:"-"`
+}
+
+func (c *Sha512Wrapper) Define(api frontend.API) error {
+	hash := c.Hash
+	if hash == 0 {
+		hash = crypto.SHA512
+	}
+	digest, err := hashBytes(api, hash, c.Data)
+	if err != nil {
+		return err
+	}
+	for i := range c.ExpectHash {
+		api.AssertIsEqual(c.ExpectHash[i], digest[i])
+	}
+	return nil
+}
+
+func TestSha512(t *testing.T) {
+	assert := test.NewAssert(t)
+	// 96 bytes like R || A || M of Ed25519 fit one block, 120 bytes span two blocks after padding.
+	for _, length := range []int{96, 120} {
+		data := make([]byte, length)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+		hashSum := sha512.Sum512(data)
+		circuit := Sha512Wrapper{
+			Data:       BytesToFrontVariable(data),
+			ExpectHash: BytesToFrontVariable(hashSum[:]),
+		}
+		assignment := Sha512Wrapper{
+			Data:       BytesToFrontVariable(data),
+			ExpectHash: BytesToFrontVariable(hashSum[:]),
+		}
+		err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		// A wrong digest must not be accepted.
+		hashSum[0] ^= 1
+		assignment.ExpectHash = BytesToFrontVariable(hashSum[:])
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.Error(err)
+	}
+}
+
+func TestSha384(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, length := range []int{3, 120} {
+		data := make([]byte, length)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+		hashSum := sha512.Sum384(data)
+		circuit := Sha512Wrapper{
+			Data:       BytesToFrontVariable(data),
+			ExpectHash: BytesToFrontVariable(hashSum[:]),
+			Hash:       crypto.SHA384,
+		}
+		assignment := Sha512Wrapper{
+			Data:       BytesToFrontVariable(data),
+			ExpectHash: BytesToFrontVariable(hashSum[:]),
+		}
+		err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		hashSum[len(hashSum)-1] ^= 1
+		assignment.ExpectHash = BytesToFrontVariable(hashSum[:])
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.Error(err)
+	}
+}
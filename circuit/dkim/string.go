@@ -0,0 +1,175 @@
+package dkim
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// newLowerTable returns the lookup table mapping a byte to its ASCII lowercase.
+func newLowerTable(api frontend.API) logderivlookup.Table {
+	table := logderivlookup.New(api)
+	for b := 0; b < 256; b++ {
+		if b >= 'A' && b <= 'Z' {
+			table.Insert(b + 'a' - 'A')
+		} else {
+			table.Insert(b)
+		}
+	}
+	return table
+}
+
+// bigEndianData returns the slice in big-endian together with the data mask, 1 for the data and 0 for the padding.
+func (c *SliceApi) bigEndianData(s PaddingSlice) (PaddingSlice, []frontend.Variable) {
+	bigEndian := s.Clone()
+	if bigEndian.IsLittleEndian {
+		bigEndian = bigEndian.Reverse(c.api)
+	}
+	if len(bigEndian.Slice) < 2 {
+		// StepMask needs two elements, a single element is data if the padding is -1.
+		mask := make([]frontend.Variable, len(bigEndian.Slice))
+		for i := range mask {
+			mask[i] = c.api.IsZero(c.api.Add(bigEndian.Padding, 1))
+		}
+		return bigEndian, mask
+	}
+	return bigEndian, selector.StepMask(c.api, len(bigEndian.Slice), c.api.Add(bigEndian.Padding, 1), 0, 1)
+}
+
+// IsEmpty returns 1 if the slice holds no data, otherwise 0.
+func (c *SliceApi) IsEmpty(s PaddingSlice) frontend.Variable {
+	if len(s.Slice) == 0 {
+		return 1
+	}
+	_, mask := c.bigEndianData(s)
+	length := frontend.Variable(0)
+	for i := range mask {
+		length = c.api.Add(length, mask[i])
+	}
+	return c.api.IsZero(length)
+}
+
+// ToLower returns the slice with the ASCII letters of its data lowercased, the bytes must be in [0, 256).
+func (c *SliceApi) ToLower(s PaddingSlice) PaddingSlice {
+	lower := newLowerTable(c.api)
+	result := s.Clone()
+	for i := range result.Slice {
+		result.Slice[i] = lower.Lookup(result.Slice[i])[0]
+	}
+	return result
+}
+
+// IsEqual returns 1 if the data of the slices are equal, otherwise 0. The slices may differ in capacity and endianness.
+func (c *SliceApi) IsEqual(a, b PaddingSlice) frontend.Variable {
+	api := c.api
+	a, aMask := c.bigEndianData(a)
+	b, _ = c.bigEndianData(b)
+	// Both data are right aligned, the shorter slice is extended with leading padding.
+	for len(a.Slice) < len(b.Slice) {
+		a.Slice = append([]frontend.Variable{0}, a.Slice...)
+		aMask = append([]frontend.Variable{0}, aMask...)
+		a.Padding = api.Add(a.Padding, 1)
+	}
+	for len(b.Slice) < len(a.Slice) {
+		b.Slice = append([]frontend.Variable{0}, b.Slice...)
+		b.Padding = api.Add(b.Padding, 1)
+	}
+	mismatches := api.Sub(1, api.IsZero(api.Sub(a.Padding, b.Padding)))
+	for i := range a.Slice {
+		mismatches = api.Add(mismatches, api.Sub(1, api.IsZero(api.Mul(aMask[i], api.Sub(a.Slice[i], b.Slice[i])))))
+	}
+	return api.IsZero(mismatches)
+}
+
+// IsEqualString returns 1 if the data of the slice equals the constant str, otherwise 0.
+func (c *SliceApi) IsEqualString(s PaddingSlice, str []byte) frontend.Variable {
+	if len(str) > len(s.Slice) {
+		return 0
+	}
+	return c.IsEqual(s, BytesToFixPadding(str, false, len(s.Slice)))
+}
+
+// AssertIsEqualString asserts that the data of the slice equals the constant str.
+func (c *SliceApi) AssertIsEqualString(s PaddingSlice, str []byte) {
+	c.api.AssertIsEqual(c.IsEqualString(s, str), 1)
+}
+
+// HasPrefix returns 1 if the data of the slice starts with the constant prefix, otherwise 0.
+func (c *SliceApi) HasPrefix(s PaddingSlice, prefix []byte) frontend.Variable {
+	api := c.api
+	if len(prefix) > len(s.Slice) {
+		return 0
+	}
+	if len(prefix) == 0 {
+		return 1
+	}
+	bigEndian, mask := c.bigEndianData(s)
+	start := api.Add(bigEndian.Padding, 1)
+	// The prefix fits in the data, then every byte of it is picked from the data start.
+	matched := mask[len(mask)-len(prefix)]
+	for i := range prefix {
+		b := selector.Mux(api, api.Mul(api.Add(start, i), matched), bigEndian.Slice...)
+		matched = api.Mul(matched, api.IsZero(api.Sub(b, prefix[i])))
+	}
+	return matched
+}
+
+// AssertHasPrefix asserts that the data of the slice starts with the constant prefix.
+func (c *SliceApi) AssertHasPrefix(s PaddingSlice, prefix []byte) {
+	c.api.AssertIsEqual(c.HasPrefix(s, prefix), 1)
+}
+
+// HasSuffix returns 1 if the data of the slice ends with the constant suffix, otherwise 0.
+func (c *SliceApi) HasSuffix(s PaddingSlice, suffix []byte) frontend.Variable {
+	api := c.api
+	if len(suffix) > len(s.Slice) {
+		return 0
+	}
+	if len(suffix) == 0 {
+		return 1
+	}
+	// The suffix takes the last bytes of the big-endian slice, which must all be data.
+	bigEndian, mask := c.bigEndianData(s)
+	offset := len(bigEndian.Slice) - len(suffix)
+	matched := mask[offset]
+	for i := range suffix {
+		matched = api.Mul(matched, api.IsZero(api.Sub(bigEndian.Slice[offset+i], suffix[i])))
+	}
+	return matched
+}
+
+// AssertHasSuffix asserts that the data of the slice ends with the constant suffix.
+func (c *SliceApi) AssertHasSuffix(s PaddingSlice, suffix []byte) {
+	c.api.AssertIsEqual(c.HasSuffix(s, suffix), 1)
+}
+
+// IndexOf returns the index of the first occurrence of the constant substr in the data of the slice,
+// counted from the data start, and whether it is found. The index is 0 when it is not found.
+func (c *SliceApi) IndexOf(s PaddingSlice, substr []byte) (frontend.Variable, frontend.Variable) {
+	api := c.api
+	if len(substr) == 0 {
+		return 0, 1
+	}
+	if len(substr) > len(s.Slice) {
+		return 0, 0
+	}
+	bigEndian, mask := c.bigEndianData(s)
+	start := api.Add(bigEndian.Padding, 1)
+	index, found := frontend.Variable(0), frontend.Variable(0)
+	for i := 0; i+len(substr) <= len(bigEndian.Slice); i++ {
+		matched := mask[i]
+		for j := range substr {
+			matched = api.Mul(matched, api.IsZero(api.Sub(bigEndian.Slice[i+j], substr[j])))
+		}
+		first := api.Mul(matched, api.Sub(1, found))
+		index = api.Add(index, api.Mul(first, api.Sub(i, start)))
+		found = api.Add(found, first)
+	}
+	return index, found
+}
+
+// Contains returns 1 if the data of the slice contains the constant substr, otherwise 0.
+func (c *SliceApi) Contains(s PaddingSlice, substr []byte) frontend.Variable {
+	_, found := c.IndexOf(s, substr)
+	return found
+}
@@ -0,0 +1,126 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+type StringWrapper struct {
+	Data   PaddingSlice
+	Other  PaddingSlice
+	Result frontend.Variable
+	Index  frontend.Variable
+	Op     string `gnark:"-"`
+	Str    []byte `gnark:"-"`
+}
+
+func (c *StringWrapper) Define(api frontend.API) error {
+	sliceApi := NewSliceApi(api)
+	var result frontend.Variable
+	switch c.Op {
+	case "lower":
+		result = sliceApi.IsEqual(sliceApi.ToLower(c.Data), c.Other)
+	case "equal":
+		result = sliceApi.IsEqual(c.Data, c.Other)
+	case "equalString":
+		result = sliceApi.IsEqualString(c.Data, c.Str)
+	case "prefix":
+		result = sliceApi.HasPrefix(c.Data, c.Str)
+	case "suffix":
+		result = sliceApi.HasSuffix(c.Data, c.Str)
+	case "contains":
+		result = sliceApi.Contains(c.Data, c.Str)
+	case "index":
+		var index frontend.Variable
+		index, result = sliceApi.IndexOf(c.Data, c.Str)
+		api.AssertIsEqual(c.Index, index)
+	}
+	api.AssertIsEqual(c.Result, result)
+	return nil
+}
+
+// bytesToLittleEndian converts a byte array to a little-endian PaddingSlice with trailing zeros.
+func bytesToLittleEndian(src []byte, maxLength int) PaddingSlice {
+	slice := make([]byte, maxLength)
+	copy(slice, src)
+	return BytesToPadding(slice, true, len(src))
+}
+
+func TestStringGadgets(t *testing.T) {
+	assert := test.NewAssert(t)
+	length := 32
+	for _, tc := range []struct {
+		op     string
+		data   string
+		other  string
+		str    string
+		result int
+		index  int
+	}{
+		{op: "lower", data: "From:Bob@FoxMail.COM", other: "from:bob@foxmail.com", result: 1},
+		{op: "lower", data: "From:Bob", other: "from:bob@", result: 0},
+		{op: "equal", data: "foxmail.com", other: "foxmail.com", result: 1},
+		{op: "equal", data: "foxmail.com", other: "oxmail.com", result: 0},
+		{op: "equal", data: "", other: "", result: 1},
+		{op: "equalString", data: "gmail.com", str: "gmail.com", result: 1},
+		{op: "equalString", data: "gmail.co", str: "gmail.com", result: 0},
+		{op: "prefix", data: "subject:Approve 0x1", str: "subject:", result: 1},
+		{op: "prefix", data: "subject", str: "subject:", result: 0},
+		{op: "prefix", data: "from:subject:", str: "subject:", result: 0},
+		{op: "suffix", data: "bob@foxmail.com", str: "@foxmail.com", result: 1},
+		{op: "suffix", data: "bob@foxmail.co", str: "@foxmail.com", result: 0},
+		{op: "suffix", data: "foxmail.com", str: "@foxmail.com", result: 0},
+		{op: "contains", data: "bob@foxmail.com", str: "mail", result: 1},
+		{op: "contains", data: "bob@foxmail.com", str: "gmail", result: 0},
+		{op: "index", data: "a=1; b=2; b=3", str: "b=", result: 1, index: 5},
+		{op: "index", data: "a=1; b=2", str: "c=", result: 0, index: 0},
+		{op: "index", data: "b=", str: "b=", result: 1, index: 0},
+	} {
+		for _, isLittleEndian := range []bool{false, true} {
+			circuit := StringWrapper{
+				Data:  BytesToFixPadding(nil, false, length),
+				Other: BytesToFixPadding(nil, false, length-4),
+				Op:    tc.op,
+				Str:   []byte(tc.str),
+			}
+			data := BytesToFixPadding([]byte(tc.data), false, length)
+			if isLittleEndian {
+				circuit.Data = bytesToLittleEndian(nil, length)
+				data = bytesToLittleEndian([]byte(tc.data), length)
+			}
+			assignment := StringWrapper{
+				Data:   data,
+				Other:  BytesToFixPadding([]byte(tc.other), false, length-4),
+				Result: tc.result,
+				Index:  tc.index,
+			}
+			err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+			assert.NoError(err, tc.op, tc.data)
+		}
+	}
+}
+
+func TestStringGadgetConstraints(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, op := range []string{"lower", "equal", "equalString", "prefix", "suffix", "index"} {
+		counts := make([]int, 0)
+		for _, length := range []int{64, 128} {
+			circuit := StringWrapper{
+				Data:  BytesToFixPadding(nil, false, length),
+				Other: BytesToFixPadding(nil, false, length),
+				Op:    op,
+				Str:   []byte("dkim-signature:"),
+			}
+			ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
+			assert.NoError(err)
+			counts = append(counts, ccs.GetNbConstraints())
+		}
+		t.Logf("%s: %d constraints for 64 bytes, %d for 128 bytes", op, counts[0], counts[1])
+		// The gadgets are linear in the slice length for a constant string.
+		assert.LessOrEqual(counts[1], 2*counts[0], op)
+	}
+}
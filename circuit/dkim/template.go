@@ -1,8 +1,53 @@
 package dkim
 
-import "github.com/doubiliu/zk-email/utils"
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+	"slices"
 
-var rsaPubkeyTemplate = `v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQCl2Qrp5KF1uJnQSO0YuwInVPISQRrUciXtg/5hnQl6ed+UmYvWreLyuiyaiSd9X9Zu+aZQoeKm67HCxSMpC6G2ar0NludsXW69QdfzUpB5I6fzaLW8rl/RyeGkiQ3D66kvadK1wlNfUI7Dt9WtnUs8AFz/15xvODzgTMFJDiAcAwIDAQAB`
+	"github.com/doubiliu/zk-email/utils"
+)
+
+// MailTemplate describes the circuit shape of a mail provider.
+type MailTemplate struct {
+	// Header is a sample of the signed headers, the length of each field bounds the circuit slices.
+	Header string
+	// KeyBits is the RSA modulus size of the provider's DKIM key, it is ignored for ed25519-sha256 templates.
+	KeyBits int
+	// Options are the circuit modes of the template, the options of the caller are applied after them.
+	Options []Option
+	// Capacities, when not nil, bound the circuit slices instead of the Header, which is then ignored,
+	// see UniversalTemplate.
+	Capacities *Capacities
+}
+
+// DomainMailTypeSuffix selects the domain variant of a mail type, e.g. "gmail-domain".
+const DomainMailTypeSuffix = "-domain"
+
+// DomainCapacity is the maximum byte length of the sender domain in the domain variant.
+const DomainCapacity = 64
+
+// DomainTemplate returns the domain variant of the mail template, which only commits to the domain of the From address.
+func DomainTemplate(template MailTemplate) MailTemplate {
+	template.Options = append(slices.Clone(template.Options), WithSenderDomain(DomainCapacity))
+	return template
+}
+
+// rsaPubkeyTemplate returns the DNS TXT record of a placeholder RSA key of the byte width,
+// the circuit template only takes the key size from it.
+func rsaPubkeyTemplate(width int) (string, error) {
+	n := new(big.Int).Lsh(big.NewInt(1), uint(width*8-1))
+	n.SetBit(n, 0, 1)
+	der, err := x509.MarshalPKIXPublicKey(&rsa.PublicKey{N: n, E: 65537})
+	if err != nil {
+		return "", err
+	}
+	return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+}
+
+var ed25519PubkeyTemplate = `v=DKIM1; k=ed25519; p=iojj3XQJ8ZX9UtstPLpdcspnCb8dlBIb83SIAbQPb1w=`
 
 var GmailTemplate = utils.FixupNewlines(`to:xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
 subject:xxxxxxxxxxxxxxx
@@ -56,3 +101,61 @@ To: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
 Subject: xxxxxxxxxxxxxxx
 Date: xxx, xx xxx xxxx xx:xx:xx +xxxx (xxx)
 dkim-signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=foxmail.com; s=xxxxxxxxxx; t=xxxxxxxxxx; bh=puPE4wrCV5YcrcfuFwepbL9s4gzEO/Omu6K0zc+lG5k=; h=From:To:Subject:Date; b=puBeLAmUrZcLTca/kAoDQaW1lUTidBFWtU5oEwIA3dJoeF/8wol9exglsHJFq58budhpmES0VTMpCr4v3rb4TH0gJ+r/Z3k1009nMQlBh3gTWJAG6LUgvXDxlQQBZRM4NlhrgenWw7yebQlbltmOfdY3Uy/mkiidj8fMNEfI3I4=`)
+
+var YahooTemplate = utils.FixupNewlines(`Date: xxx, xx xxx xxxx xx:xx:xx +xxxx (xxx)
+From: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+To: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Subject: xxxxxxxxxxxxxxx
+References: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=yahoo.com; s=xxxxxxxxxx; t=xxxxxxxxxx; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; h=Date:From:To:Subject:References:From:Subject:Reply-To; b=Ij4xInGk04s2xm9V6zpM9msPHPsMya3mmgZR2HZUdogL3juJcU/90Sp3H5dPFd9zDAl4ir1wqoxfFuVKFsQF3kF09q9TY85roXKVtnQeDDGe3720HElE+OIC/s0zbiWsUIwv5FSweeJku5dWnDI6Ui/H8f79/wgZbcGJ/VHy3B6vNsXovhdnUElYSZVe9NQwtpje6n0p+csP1CTvPVygr9is1xfqPPJ2y82zzotUcATBnwjYa7yrkMIIKRhxshY/vdmAIHiswg/ndxYIazSKu8U/1uFYIFW8GVH/TZ8WaFckAUw4vvxyUaOVCcL/eTggR039l60AIB4IzLPx6LDUwA==`)
+
+var ProtonTemplate = utils.FixupNewlines(`Date: xxx, xx xxx xxxx xx:xx:xx +xxxx (xxx)
+To: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+From: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Subject: xxxxxxxxxxxxxxx
+Message-ID: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Feedback-ID: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=proton.me;
+	s=xxxxxxxxxx; t=xxxxxxxxxx; x=xxxxxxxxxx;
+	bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=;
+	h=Date:To:From:Subject:Message-ID:Feedback-ID:From:To:Cc:Date:Subject:
+	 Reply-To:Feedback-ID:Message-ID:BIMI-Selector;
+	b=N6XmQoEYGEneV1TnSucApeb1K7UrlOOvwf69/SJnrmLb8Bw5YcGTMHb2sO/oYeYPMGWMbB
+	 MUrN7QTun2xOEBmo7x8jVYL7pJnMIMs1PJBw/L2ENVVXXdf3QRqPdi/PwgvP1yFd1/PVcY
+	 Wl6yfZNXIYLn/08Ph0Jeld9PHhiA5felrtXoJG1XU8PpqTBd0Cl8Mj7FuUJ3LkouyXTi8w
+	 Yha5kdoRlHygQYz72FTGXmiU0KMo3/tbQcBtriqtE4wZ0V1flU+vwyeoGR8z11nFOCsxTl
+	 PRSqlkkc41NlnVD/12x45Dddd+yu/S2fDNR9ehrGoGw33UbRZvcIr54kvgPI7Q==`)
+
+var QQTemplate = utils.FixupNewlines(`From: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+To: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Subject: xxxxxxxxxxxxxxx
+Date: xxx, xx xxx xxxx xx:xx:xx +xxxx (xxx)
+dkim-signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=qq.com; s=xxxxxxxxxx; t=xxxxxxxxxx; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; h=From:To:Subject:Date; b=huK7rGt5gsMN/GvxxOfssIRy94kUgwT5bymif4tetrXWKDPz0E/HGyRFtNTlxDWf098Q0Rrzfql3Xwczj9MR5PwUMj108MRO/QotZY2pQgXakfM5jJfF4a0VmogDLp/DW9yUVB4hT1gHplpuH5veHJGsMsLmDaVg4tUmdXr0PSk=`)
+
+var NetEaseTemplate = utils.FixupNewlines(`Date: xxx, xx xxx xxxx xx:xx:xx +xxxx (xxx)
+From: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Subject: xxxxxxxxxxxxxxx
+Content-Type: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+MIME-Version: 1.0
+Message-ID: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=163.com;
+	s=xxxxxxxxxx; h=Date:From:Subject:Content-Type:MIME-Version:
+	Message-ID; bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=; b=fspypnG6G+6F1K+W/lyG7cSMhXgkUtqKUZTe0Ldaeas3mP9rkFLWTwI8l1vtvw0PmxdPd9
+	 FXyDzQ3Zk2FVG0r/1FfqpS5l9h4PmAMarMnNjrtwasYvYViAkfWI2xxYOg7AJXDv3Lpogd
+	 Ga1i6KW0JCLjqMIbqlPKL+oo02nBPuA=`)
+
+var ZohoTemplate = utils.FixupNewlines(`Date: xxx, xx xxx xxxx xx:xx:xx +xxxx (xxx)
+From: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+To: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Message-Id: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Subject: xxxxxxxxxxxxxxx
+MIME-Version: 1.0
+Content-Type: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+Content-Transfer-Encoding: xxxxxxxxxxxxxxxxxxxxxxxxx
+DKIM-Signature: v=1; a=rsa-sha256; t=xxxxxxxxxx; c=relaxed/relaxed;
+	s=xxxxxxxxxx; d=zoho.com; i=xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx;
+	h=Date:Date:From:From:To:To:Message-Id:Message-Id:Subject:Subject:MIME-Version:Content-Type:Content-Transfer-Encoding:Reply-To:Cc;
+	bh=TUhAb8ghWEby4pRf/XzeMukIBDw8mez9nxjh5MZrYV0=;
+	b=WhWfaQyF40xzu68uRyRaTDaRDoj614zliU9OGvOn7jB0pOkhQMHE4tqPJM0Asl5QH6k2wO
+	 b7wC5WUmRfkn/QBUV51FXQ3btYGxjTK0hjJFwse5tubSSB1BjYZxIiKWLsf1AkxwFpFr6L
+	 zsDo2Cv59NT2pTWoU7EmbhBb0HLFSq4=`)
@@ -0,0 +1,70 @@
+package dkim
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// MaxTimestampDigits bounds the decimal digits of the t= tag, RFC 6376 allows at most 12.
+const MaxTimestampDigits = 12
+
+// signatureTimestampPattern matches the relaxed canonical DKIM-Signature header trimmed after "b=",
+// the group captures the digits of the t= tag, see signatureDomainPattern.
+const signatureTimestampPattern = `dkim-signature:(?:[^;]*;)* ?t ?= ?([0-9]+) ?(?:;[^\r\n]*)?`
+
+// signatureTimestampRegex is the compiled signatureTimestampPattern.
+var signatureTimestampRegex = mustCompileRegex(signatureTimestampPattern)
+
+// ExtractSignatureTimestamp parses the t= tag of the trimmed DKIM-Signature header slice and returns
+// the signing time in seconds since the Unix epoch. captureMask marks the digits, see SignatureTimestampMask.
+func (c *SliceApi) ExtractSignatureTimestamp(trimmedHeader PaddingSlice, captureMask []frontend.Variable) (frontend.Variable, error) {
+	api := c.api
+	captured, err := c.AssertMatchRegex(signatureTimestampRegex, trimmedHeader, captureMask)
+	if err != nil {
+		return nil, err
+	}
+	if trimmedHeader.IsLittleEndian {
+		captured = slices.Clone(captured)
+		slices.Reverse(captured)
+	}
+	// The regex captures one run of digits, which are never 0.
+	timestamp, digits := frontend.Variable(0), frontend.Variable(0)
+	for _, b := range captured {
+		isDigit := api.Sub(1, api.IsZero(b))
+		timestamp = api.Select(isDigit, api.Add(api.Mul(timestamp, 10), api.Sub(b, '0')), timestamp)
+		digits = api.Add(digits, isDigit)
+	}
+	api.AssertIsLessOrEqual(digits, MaxTimestampDigits)
+	return timestamp, nil
+}
+
+// SignatureTimestamp returns the t= signing time of the DKIM signature of the email in seconds since the Unix epoch.
+func SignatureTimestamp(message string) (uint64, error) {
+	trimmedHeader, err := signatureTrimmedHeader(message)
+	if err != nil {
+		return 0, err
+	}
+	return signatureTimestamp([]byte(trimmedHeader))
+}
+
+// signatureTimestamp parses the t= tag of the canonical trimmed DKIM-Signature header like the circuit.
+func signatureTimestamp(trimmedHeader []byte) (uint64, error) {
+	digits, ok := signatureTag(signatureTimestampRegex, trimmedHeader)
+	if !ok {
+		return 0, errors.New("no timestamp found in the DKIM signature")
+	}
+	if len(digits) > MaxTimestampDigits {
+		return 0, errors.New("DKIM timestamp has too many digits")
+	}
+	return strconv.ParseUint(string(digits), 10, 64)
+}
+
+// SignatureTimestampMask returns the capture mask witness of the trimmed DKIM-Signature header padded to length bytes,
+// see ExtractSignatureTimestamp.
+func SignatureTimestampMask(trimmedHeader []byte, length int) ([]frontend.Variable, error) {
+	mask, _, err := signatureTimestampRegex.CaptureMask(trimmedHeader, length)
+	return mask, err
+}
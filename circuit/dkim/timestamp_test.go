@@ -0,0 +1,59 @@
+package dkim
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type TimestampWrapper struct {
+	TrimmedHeader PaddingSlice
+	Mask          []frontend.Variable
+	Timestamp     frontend.Variable `gnark:",public"`
+}
+
+func (c *TimestampWrapper) Define(api frontend.API) error {
+	sliceApi := NewSliceApi(api)
+	timestamp, err := sliceApi.ExtractSignatureTimestamp(c.TrimmedHeader, c.Mask)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(c.Timestamp, timestamp)
+	return nil
+}
+
+func TestExtractSignatureTimestamp(t *testing.T) {
+	assert := test.NewAssert(t)
+	length := 80
+	trimmedHeader := []byte("dkim-signature:v=1; d=foxmail.com; t=1762244918; x=1763113014; bh=abc=; b=")
+	mask, err := SignatureTimestampMask(trimmedHeader, length)
+	assert.NoError(err)
+	circuit := TimestampWrapper{
+		TrimmedHeader: BytesToFixPadding(nil, false, length),
+		Mask:          make([]frontend.Variable, length),
+	}
+	assignment := TimestampWrapper{
+		TrimmedHeader: BytesToFixPadding(trimmedHeader, false, length),
+		Mask:          mask,
+		Timestamp:     1762244918,
+	}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	// The x= expiration can not be taken as the signing time.
+	assignment.Timestamp = 1763113014
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestSignatureTimestamp(t *testing.T) {
+	assert := test.NewAssert(t)
+	timestamp, err := SignatureTimestamp(GmailTestData)
+	assert.NoError(err)
+	assert.Equal(uint64(1762508214), timestamp)
+	_, err = SignatureTimestamp(OutLookTestData)
+	assert.Error(err)
+	_, assignment := foxmailAssignment(assert, WithTimestamp())
+	assert.Equal([]frontend.Variable{uint64(1762244918)}, assignment.Timestamp)
+}
@@ -0,0 +1,120 @@
+package dkim
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// UniversalMailType is the mail type of the universal circuit with a 2048-bit key and the DefaultCapacities.
+const UniversalMailType = "universal"
+
+// Ed25519KeyBits is the key size of the Ed25519 circuits, see DKIMVerifier.KeyBits. The universal template
+// of this key size verifies ed25519-sha256 signatures.
+const Ed25519KeyBits = 256
+
+// Capacities are the maximum byte lengths of the slices of a universal DKIM verifier circuit.
+// Unlike a header sample, which fixes the layout of one provider, the capacities accept the signed headers
+// of any provider that fit them, so that one phase2 ceremony covers all senders.
+type Capacities struct {
+	// Prefix bounds the canonical signed headers before each revealed header.
+	Prefix int `yaml:"prefix"`
+	// Revealed bounds each canonical revealed header.
+	Revealed int `yaml:"revealed"`
+	// Suffix bounds the canonical signed headers after the last revealed header.
+	Suffix int `yaml:"suffix"`
+	// SigPrefix bounds the trimmed DKIM-Signature header up to the bh= value.
+	SigPrefix int `yaml:"sigPrefix"`
+	// SigSuffix bounds the trimmed DKIM-Signature header from the end of the bh= value to the empty b= value.
+	SigSuffix int `yaml:"sigSuffix"`
+}
+
+// DefaultCapacities fit the signed headers of the providers of the mail templates.
+var DefaultCapacities = Capacities{
+	Prefix:    512,
+	Revealed:  128,
+	Suffix:    512,
+	SigPrefix: 256,
+	SigSuffix: 192,
+}
+
+// UniversalTemplate returns the mail template of the universal circuit of the RSA key size and the capacities,
+// or of Ed25519 with Ed25519KeyBits. The hash function of RSA is SHA-256 unless it is selected by WithHash.
+func UniversalTemplate(keyBits int, capacities Capacities) MailTemplate {
+	return MailTemplate{KeyBits: keyBits, Capacities: &capacities}
+}
+
+// check checks that every capacity is positive.
+func (c *Capacities) check() error {
+	if c.Prefix <= 0 || c.Revealed <= 0 || c.Suffix <= 0 || c.SigPrefix <= 0 || c.SigSuffix <= 0 {
+		return errors.New("capacities must be positive")
+	}
+	return nil
+}
+
+// capacityHeader returns the smallest signed headers with the revealed headers, the hash function and
+// the canonicalization of the config, the circuit built from them is resized to the capacities.
+func capacityHeader(cfg VerifierConfig, keyBits int) (string, error) {
+	var algo string
+	switch cfg.Hash {
+	case 0, crypto.SHA256:
+		algo = "rsa-sha256"
+	case crypto.SHA1:
+		algo = "rsa-sha1"
+	default:
+		return "", fmt.Errorf("hash function %v has no DKIM signing algorithm", cfg.Hash)
+	}
+	hash := cfg.Hash
+	if hash == 0 {
+		hash = crypto.SHA256
+	}
+	sigSize := (keyBits + 7) / 8
+	if keyBits == Ed25519KeyBits {
+		if hash != crypto.SHA256 {
+			return "", errors.New("Ed25519 signatures require the SHA-256 hash function")
+		}
+		algo, sigSize = "ed25519-sha256", ed25519.SignatureSize
+	}
+	canonicalization := cfg.Canonicalization
+	if canonicalization == "" {
+		canonicalization = "relaxed/relaxed"
+	}
+	var header strings.Builder
+	for _, name := range cfg.RevealedHeaders {
+		header.WriteString(name + ":x\r\n")
+	}
+	fmt.Fprintf(&header, "DKIM-Signature: v=1; a=%s; c=%s; d=x; s=x; h=%s; bh=%s; b=%s\r\n",
+		algo, canonicalization, strings.Join(cfg.RevealedHeaders, ":"),
+		base64.StdEncoding.EncodeToString(make([]byte, hash.Size())),
+		base64.StdEncoding.EncodeToString(make([]byte, sigSize)))
+	return header.String(), nil
+}
+
+// resize sizes the slices of the circuit template and the masks indexing them to the capacities.
+func (c *CustomDKIMVerifierWrapper[T]) resize(capacities *Capacities) {
+	for i := range c.Header.HiddenData {
+		capacity := capacities.Prefix
+		if i == len(c.Header.HiddenData)-1 {
+			capacity = capacities.Suffix
+		}
+		c.Header.HiddenData[i] = BytesToFixPadding(nil, false, capacity)
+	}
+	for i := range c.Header.SpecifyData {
+		c.Header.SpecifyData[i] = BytesToFixPadding(nil, false, capacities.Revealed)
+	}
+	c.Signature.SigPrefix = BytesToFixPadding(nil, false, capacities.SigPrefix)
+	c.Signature.SigSuffix = BytesToFixPadding(nil, false, capacities.SigSuffix)
+	if len(c.SenderMask) != 0 {
+		c.SenderMask = BytesToFrontVariable(make([]byte, capacities.Revealed))
+	}
+	for _, mask := range []*[]frontend.Variable{&c.SignatureDomainMask, &c.SelectorMask, &c.TimestampMask} {
+		if len(*mask) != 0 {
+			*mask = BytesToFrontVariable(make([]byte, c.trimmedHeaderLength()))
+		}
+	}
+}
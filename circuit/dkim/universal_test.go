@@ -0,0 +1,120 @@
+package dkim
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+// lookupTestRecord returns the DNS TXT record of the DKIM signature of the mail from the fake DNS client.
+func lookupTestRecord(assert *test.Assert, mail string) string {
+	signature, err := emailSignature(mail)
+	assert.NoError(err)
+	txtRecords, err := client.LookupTxt(signature.TxtRecordName())
+	assert.NoError(err)
+	return txtRecords[0]
+}
+
+func TestUniversalDKIMVerifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	// One circuit fits the signed headers of both gmail and outlook.
+	capacities := Capacities{Prefix: 192, Revealed: 64, Suffix: 320, SigPrefix: 224, SigSuffix: 64}
+	verifier, err := NewCustomDKIMVerifierWrapper(UniversalTemplate(2048, capacities))
+	assert.NoError(err)
+	assert.Equal(2048, verifier.KeyBits())
+	for _, mail := range []string{GmailTestData, OutLookTestData} {
+		assignment, err := verifier.NewAssignment(mail, lookupTestRecord(assert, mail))
+		assert.NoError(err)
+		err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+	// Signed headers beyond the capacities do not fit.
+	small, err := NewCustomDKIMVerifierWrapper(UniversalTemplate(2048, Capacities{Prefix: 192, Revealed: 64, Suffix: 128, SigPrefix: 224, SigSuffix: 64}))
+	assert.NoError(err)
+	_, err = small.NewAssignment(OutLookTestData, lookupTestRecord(assert, OutLookTestData))
+	assert.Error(err)
+}
+
+func TestUniversalDKIMVerifierTemplate(t *testing.T) {
+	assert := test.NewAssert(t)
+	verifier, err := GetCustomDKIMVerifierWrapper(UniversalMailType)
+	assert.NoError(err)
+	template := verifier.(*CustomDKIMVerifierWrapper[Mod1e2048])
+	assert.Equal(DefaultCapacities.Prefix, len(template.Header.HiddenData[0].Slice))
+	assert.Equal(DefaultCapacities.Revealed, len(template.Header.SpecifyData[0].Slice))
+	assert.Equal(DefaultCapacities.Suffix, len(template.Header.HiddenData[1].Slice))
+	assert.Equal(DefaultCapacities.SigPrefix, len(template.Signature.SigPrefix.Slice))
+	assert.Equal(DefaultCapacities.SigSuffix, len(template.Signature.SigSuffix.Slice))
+	for _, mail := range []string{GmailTestData, OutLookTestData, ICloudTestData, NGDTestData} {
+		assignment, err := verifier.NewAssignment(mail, lookupTestRecord(assert, mail))
+		assert.NoError(err)
+		header := assignment.(*CustomDKIMVerifierWrapper[Mod1e2048]).Header
+		assert.Equal(DefaultCapacities.Prefix, len(header.HiddenData[0].Slice))
+		assert.Equal(DefaultCapacities.Suffix, len(header.HiddenData[1].Slice))
+	}
+	// The key size and the hash function are still part of the circuit.
+	_, err = verifier.NewAssignment(FoxmailTestData, lookupTestRecord(assert, FoxmailTestData))
+	assert.Error(err)
+	sha1Verifier, err := NewCustomDKIMVerifierWrapper(UniversalTemplate(2048, DefaultCapacities), WithHash(crypto.SHA1))
+	assert.NoError(err)
+	_, err = sha1Verifier.NewAssignment(GmailTestData, lookupTestRecord(assert, GmailTestData))
+	assert.Error(err)
+	// SHA-384 and SHA-512 have no DKIM signing algorithm.
+	for _, h := range []crypto.Hash{crypto.SHA384, crypto.SHA512} {
+		_, err = NewCustomDKIMVerifierWrapper(UniversalTemplate(2048, DefaultCapacities), WithHash(h))
+		assert.Error(err)
+	}
+	// Every capacity must be positive.
+	_, err = NewCustomDKIMVerifierWrapper(UniversalTemplate(2048, Capacities{Prefix: 512, Revealed: 128}))
+	assert.Error(err)
+}
+
+func TestUniversalEd25519DKIMVerifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	verifier, err := NewCustomDKIMVerifierWrapper(UniversalTemplate(Ed25519KeyBits, DefaultCapacities))
+	assert.NoError(err)
+	assert.Equal(Ed25519KeyBits, verifier.KeyBits())
+	template := verifier.(*CustomDKIMVerifierWrapper[Ed25519Fp])
+	assert.Equal(DefaultCapacities.Revealed, len(template.Header.SpecifyData[0].Slice))
+	_, err = verifier.NewAssignment(Ed25519TestData, ed25519TxtRecord)
+	assert.NoError(err)
+	// RSA mails do not fit the Ed25519 circuit, and Ed25519 signs the SHA-256 hash.
+	_, err = verifier.NewAssignment(FoxmailTestData, lookupTestRecord(assert, FoxmailTestData))
+	assert.Error(err)
+	_, err = NewCustomDKIMVerifierWrapper(UniversalTemplate(Ed25519KeyBits, DefaultCapacities), WithHash(crypto.SHA1))
+	assert.Error(err)
+}
+
+func TestUniversalHeaderBoundary(t *testing.T) {
+	assert := test.NewAssert(t)
+	// The universal circuit leaves the layout of the signed headers to the prover, yet the From header
+	// can not be cut out of the subject.
+	verifier, err := NewCustomDKIMVerifierWrapper(UniversalTemplate(1024, DefaultCapacities), WithSenderAddress(64), WithDomainAlignment(AlignmentRelaxed))
+	assert.NoError(err)
+	template := verifier.(*CustomDKIMVerifierWrapper[Mod1e1024])
+	forged := "from:victim@example.com\r\n"
+	assignment := forgedAssignment(assert, ForgedTestData, forged, template.Config)
+	for i := range assignment.Header.HiddenData {
+		assignment.Header.HiddenData[i], err = padToTemplate(template.Header.HiddenData[i], assignment.Header.HiddenData[i], "hidden data")
+		assert.NoError(err)
+	}
+	assignment.Header.SpecifyData[0], err = padToTemplate(template.Header.SpecifyData[0], assignment.Header.SpecifyData[0], "specify data")
+	assert.NoError(err)
+	assignment.Signature.SigPrefix, err = padToTemplate(template.Signature.SigPrefix, assignment.Signature.SigPrefix, "sigPrefix data")
+	assert.NoError(err)
+	assignment.Signature.SigSuffix, err = padToTemplate(template.Signature.SigSuffix, assignment.Signature.SigSuffix, "sigSuffix data")
+	assert.NoError(err)
+	assignment.SenderMask, err = SenderMask([]byte(forged), DefaultCapacities.Revealed)
+	assert.NoError(err)
+	trimmedHeader, err := signatureTrimmedHeader(ForgedTestData)
+	assert.NoError(err)
+	assignment.SignatureDomainMask, err = SignatureDomainMask([]byte(trimmedHeader), template.trimmedHeaderLength())
+	assert.NoError(err)
+	sender, err := SenderCommitment(template.Config.Commitment, "victim@example.com", 64)
+	assert.NoError(err)
+	assignment.Sender = BigIntsToFrontVariable(sender)
+	err = test.IsSolved(verifier, assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -1,17 +1,20 @@
 package main
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"os"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/doubiliu/zk-email/circuit/dkim"
+	"github.com/doubiliu/zk-email/keyset"
 	"github.com/doubiliu/zk-email/mpc"
 	"github.com/urfave/cli/v2"
 )
@@ -58,9 +61,13 @@ var (
 	}
 	mailFlag = &cli.StringFlag{
 		Name:  "mailType",
-		Usage: "The type of mail, [gmail, outlook, foxmail, icloud]",
+		Usage: "The type of mail, [gmail, outlook, foxmail, icloud, ngd, yahoo, proton, qq, 163, zoho, universal] or a provider of --providers, with the suffix -domain only the sender domain is committed",
 		Value: "gmail",
 	}
+	providersFlag = &cli.PathFlag{
+		Name:  "providers",
+		Usage: "The provider file, or a directory of provider files, in JSON or YAML adding mail types to the built-in ones",
+	}
 	rsaPuKeyFileFlag = &cli.StringFlag{
 		Name:  "rsaPuKey",
 		Usage: "The out file path of rsaPuKey",
@@ -71,11 +78,107 @@ var (
 		Usage: "The file path of dkimData",
 		Value: "",
 	}
+	fixedExponentFlag = &cli.BoolFlag{
+		Name:  "fixedExponent",
+		Usage: "Fix the RSA public exponent to 65537 in the circuit",
+		Value: false,
+	}
+	commitmentFlag = &cli.StringFlag{
+		Name:  "commitment",
+		Usage: "The hash function of the public input commitment, [sha256, poseidon2, mimc]",
+		Value: "sha256",
+	}
+	revealHeadersFlag = &cli.StringSliceFlag{
+		Name:  "revealHeaders",
+		Usage: "The names of the signed headers hashed into the public inputs, in the order of the signed headers",
+		Value: cli.NewStringSlice("from"),
+	}
+	subjectCommandFlag = &cli.StringFlag{
+		Name:  "subjectCommand",
+		Usage: "The command prefix the revealed subject must start with, the argument after it becomes a public input",
+		Value: "",
+	}
+	commandCapacityFlag = &cli.IntFlag{
+		Name:  "commandCapacity",
+		Usage: "The maximum byte length of the subject command argument",
+		Value: 64,
+	}
+	senderCapacityFlag = &cli.IntFlag{
+		Name:  "senderCapacity",
+		Usage: "Commit to the From address of at most this byte length instead of the whole From header, 0 disables the sender mode",
+		Value: 0,
+	}
+	addressFlag = &cli.StringFlag{
+		Name:  "address",
+		Usage: "The sender email address",
+	}
+	senderDomainFlag = &cli.StringFlag{
+		Name:  "senderDomain",
+		Usage: "The sender email domain",
+	}
+	saltedSenderFlag = &cli.BoolFlag{
+		Name:  "saltedSender",
+		Usage: "Salt the sender commitment with a private salt",
+		Value: false,
+	}
+	domainAlignmentFlag = &cli.StringFlag{
+		Name:  "domainAlignment",
+		Usage: "The alignment of the DKIM d= domain and the From domain, [none, relaxed, strict], relaxed and strict require the revealed From header, the default is relaxed when the From header is revealed",
+		Value: "",
+	}
+	keyNameFlag = &cli.BoolFlag{
+		Name:  "keyName",
+		Usage: "Output the commitment of the DKIM d= domain and s= selector as a public input",
+		Value: false,
+	}
+	timestampFlag = &cli.BoolFlag{
+		Name:  "timestamp",
+		Usage: "Output the t= signing time of the DKIM signature as a public input",
+		Value: false,
+	}
+	dateTimestampFlag = &cli.BoolFlag{
+		Name:  "dateTimestamp",
+		Usage: "Output the time of the revealed Date header as a public input, for signatures without the t= tag",
+		Value: false,
+	}
+	senderSaltFlag = &cli.StringFlag{
+		Name:  "senderSalt",
+		Usage: "The hex encoded 32 bytes sender salt, the proof command generates one if it is empty",
+	}
+	// Flags for the proof binding
+	callerFlag = &cli.StringFlag{
+		Name:  "caller",
+		Usage: "The hex address of the account submitting the proof to the contract",
+	}
+	chainIDFlag = &cli.Uint64Flag{
+		Name:  "chainId",
+		Usage: "The ID of the chain the proof is submitted to",
+		Value: 1,
+	}
+	// Flags for the key set mode
+	keySetDepthFlag = &cli.IntFlag{
+		Name:  "keySetDepth",
+		Usage: "Prove the DKIM key belongs to a key set tree of this depth without revealing it, 0 disables the key set mode",
+		Value: 0,
+	}
+	keySetFileFlag = &cli.PathFlag{
+		Name:  "keySet",
+		Usage: "The file path of the key set, one DKIM DNS TXT record per line",
+	}
 	// todo
 )
 
 func main() {
-	app := &cli.App{
+	app := newApp()
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(app.Writer, fmt.Errorf("error: %v", err.Error()))
+		os.Exit(1)
+	}
+}
+
+// newApp returns the command line application of the ceremony and the prover.
+func newApp() *cli.App {
+	return &cli.App{
 		Commands: []*cli.Command{
 			{
 				Name:  "phase1",
@@ -158,12 +261,25 @@ will convert Phase1 data to common srs,each participant can execute this operati
 						Action: initCircuitPhase2,
 						Flags: []cli.Flag{
 							mailFlag,
+							providersFlag,
 							srsFileFlag,
 							outputFileFlag,
 							ccsFileFlag,
+							fixedExponentFlag,
+							commitmentFlag,
+							revealHeadersFlag,
+							subjectCommandFlag,
+							commandCapacityFlag,
+							senderCapacityFlag,
+							saltedSenderFlag,
+							domainAlignmentFlag,
+							keyNameFlag,
+							timestampFlag,
+							dateTimestampFlag,
+							keySetDepthFlag,
 						},
 						Description: `
-				phase2 init --mailType <string> --srsfile <filepath> --output <filepath> --ccs <filepath>
+				phase2 init --mailType <string> [--providers <path>] --srsfile <filepath> --output <filepath> --ccs <filepath> [--fixedExponent] [--commitment <string>] [--revealHeaders <string>...] [--subjectCommand <string> --commandCapacity <int>] [--senderCapacity <int> [--saltedSender]] [--domainAlignment <string>] [--keyName] [--timestamp | --dateTimestamp] [--keySetDepth <int>]
 			
 			will generate a phase2 file with a phase1 input, should be used by
 			the first participant to generate the first file.`,
@@ -219,6 +335,64 @@ will convert Phase1 data to common srs,each participant can execute this operati
 			Solidity verifier contract based on the input MPC phase1 and
 			phase2 files.`,
 			},
+			{
+				Name:  "keyset",
+				Usage: "Deal with the DKIM key set of the key set mode",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "root",
+						Usage:  "Export the key set root",
+						Action: exportKeySetRoot,
+						Flags: []cli.Flag{
+							mailFlag,
+							providersFlag,
+							commitmentFlag,
+							keySetDepthFlag,
+							keySetFileFlag,
+							outputFileFlag,
+						},
+						Description: `
+				keyset root --mailType <string> [--providers <path>] --commitment <string> --keySetDepth <int> --keySet <filepath> --output <filepath>
+
+			will build the key set tree and export its root for the verifier contract.`,
+					},
+					{
+						Name:   "path",
+						Usage:  "Export the inclusion proof of a key",
+						Action: exportKeySetPath,
+						Flags: []cli.Flag{
+							mailFlag,
+							providersFlag,
+							commitmentFlag,
+							keySetDepthFlag,
+							keySetFileFlag,
+							rsaPuKeyFileFlag,
+							outputFileFlag,
+						},
+						Description: `
+				keyset path --mailType <string> [--providers <path>] --commitment <string> --keySetDepth <int> --keySet <filepath> --rsaPuKey <filepath> --output <filepath>
+
+			will export the leaf index and the siblings of the key in the key set tree.`,
+					},
+				},
+			},
+			{
+				Name:   "sender",
+				Usage:  "Compute the expected sender commitment",
+				Action: computeSenderCommitment,
+				Flags: []cli.Flag{
+					addressFlag,
+					senderDomainFlag,
+					commitmentFlag,
+					senderCapacityFlag,
+					senderSaltFlag,
+				},
+				Description: `
+				sender (--address <string> | --senderDomain <string>) [--commitment <string>] [--senderCapacity <int>] [--senderSalt <hex>]
+
+			will print the sender commitment of the address, or of the domain for the -domain mail types,
+			the capacity defaults to 64 bytes for the domain.`,
+			},
 			{
 				Name:  "proof",
 				Usage: "Proving the zk proof",
@@ -227,21 +401,33 @@ will convert Phase1 data to common srs,each participant can execute this operati
 					verifyingKeyFileFlag,
 					ccsFileFlag,
 					mailFlag,
+					providersFlag,
 					rsaPuKeyFileFlag,
 					dkimDataFileFlag,
+					fixedExponentFlag,
+					commitmentFlag,
+					revealHeadersFlag,
+					subjectCommandFlag,
+					commandCapacityFlag,
+					senderCapacityFlag,
+					saltedSenderFlag,
+					senderSaltFlag,
+					domainAlignmentFlag,
+					keyNameFlag,
+					timestampFlag,
+					dateTimestampFlag,
+					keySetDepthFlag,
+					keySetFileFlag,
+					callerFlag,
+					chainIDFlag,
 				},
 				Action: provingProof,
 				Description: `
-				proof --pk <filepath> --vk <filepath> --ccs <filepath> --mailType <string> --rsaPuKey <filepath> --dkimData <filepath>
-			will generate a zk proof`,
+				proof --pk <filepath> --vk <filepath> --ccs <filepath> --mailType <string> [--providers <path>] --rsaPuKey <filepath> --dkimData <filepath> [--fixedExponent] [--commitment <string>] [--revealHeaders <string>...] [--subjectCommand <string> --commandCapacity <int>] [--senderCapacity <int> [--saltedSender [--senderSalt <hex>]]] [--domainAlignment <string>] [--keyName] [--timestamp | --dateTimestamp] [--keySetDepth <int>] --caller <hex> [--chainId <int>]
+			will generate a zk proof bound to the caller and the chain`,
 			},
 		},
 	}
-
-	if err := app.Run(os.Args); err != nil {
-		fmt.Println(fmt.Errorf("error: %v", err.Error()))
-		os.Exit(1)
-	}
 }
 
 func provingProof(ctx *cli.Context) error {
@@ -269,11 +455,15 @@ func provingProof(ctx *cli.Context) error {
 	if dkimDataFilePath == "" {
 		return errors.New("invalid dkim data file path")
 	}
+	binding, err := dkim.ParseBinding(ctx.String(callerFlag.Name), ctx.Uint64(chainIDFlag.Name))
+	if err != nil {
+		return fmt.Errorf("invalid caller address: %w", err)
+	}
 	ccs, err := mpc.ReadCCS(r1csFilePath)
 	if err != nil {
 		return err
 	}
-	fmt.Println("read ccs")
+	fmt.Fprintln(ctx.App.Writer, "read ccs")
 	pk, err := mpc.ReadProvingKey(provingKeyFilePath)
 	if err != nil {
 		return err
@@ -290,21 +480,242 @@ func provingProof(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	circuit, err := dkim.GetCustomDKIMVerifierWrapper(mailType)
+	circuit, err := newVerifier(ctx, mailType)
+	if err != nil {
+		return err
+	}
+	var keySet *dkim.KeySet
+	if ctx.Int(keySetDepthFlag.Name) != 0 {
+		keySet, err = readKeySet(ctx, circuit)
+		if err != nil {
+			return err
+		}
+	}
+	var assignment frontend.Circuit
+	switch {
+	case ctx.Bool(saltedSenderFlag.Name):
+		salt, err := senderSalt(ctx, true)
+		if err != nil {
+			return err
+		}
+		assignment, err = circuit.NewSaltedAssignment(dkimData, rsaPuKey, keySet, salt)
+		if err != nil {
+			return err
+		}
+	case keySet != nil:
+		assignment, err = circuit.NewKeySetAssignment(dkimData, rsaPuKey, keySet)
+		if err != nil {
+			return err
+		}
+	default:
+		assignment, err = circuit.NewAssignment(dkimData, rsaPuKey)
+		if err != nil {
+			return err
+		}
+	}
+	assignment.(dkim.DKIMVerifier).SetBinding(binding)
+	err = mpc.ProveCircuit(ccs, pk, vk, assignment)
 	if err != nil {
 		return err
 	}
-	assignment, err := dkim.NewAssignment(dkimData, rsaPuKey, circuit)
+	return nil
+}
+
+func computeSenderCommitment(ctx *cli.Context) error {
+	commitment, err := dkim.ParseCommitmentHash(ctx.String(commitmentFlag.Name))
 	if err != nil {
 		return err
 	}
-	err = mpc.ProveCircuit(ccs, pk, vk, assignment)
+	capacity := ctx.Int(senderCapacityFlag.Name)
+	salt, err := senderSalt(ctx, false)
 	if err != nil {
 		return err
 	}
+	var sender []*big.Int
+	switch {
+	case ctx.String(addressFlag.Name) != "" && salt != nil:
+		if capacity == 0 {
+			return errors.New("invalid sender capacity")
+		}
+		sender, err = dkim.SaltedSenderCommitment(commitment, ctx.String(addressFlag.Name), capacity, salt)
+	case ctx.String(addressFlag.Name) != "":
+		if capacity == 0 {
+			return errors.New("invalid sender capacity")
+		}
+		sender, err = dkim.SenderCommitment(commitment, ctx.String(addressFlag.Name), capacity)
+	case ctx.String(senderDomainFlag.Name) != "":
+		if capacity == 0 {
+			capacity = dkim.DomainCapacity
+		}
+		if salt != nil {
+			sender, err = dkim.SaltedSenderDomainCommitment(commitment, ctx.String(senderDomainFlag.Name), capacity, salt)
+		} else {
+			sender, err = dkim.SenderDomainCommitment(commitment, ctx.String(senderDomainFlag.Name), capacity)
+		}
+	default:
+		return errors.New("invalid sender address or domain")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.App.Writer, "Sender:", sender)
 	return nil
 }
 
+// senderSalt decodes the hex sender salt of the flag, a random salt is generated and printed if generate is set.
+func senderSalt(ctx *cli.Context, generate bool) ([]byte, error) {
+	if ctx.String(senderSaltFlag.Name) == "" {
+		if !generate {
+			return nil, nil
+		}
+		salt := make([]byte, dkim.SaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(ctx.App.Writer, "Sender salt:", hex.EncodeToString(salt))
+		return salt, nil
+	}
+	salt, err := hex.DecodeString(ctx.String(senderSaltFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	if len(salt) != dkim.SaltSize {
+		return nil, fmt.Errorf("sender salt must be %d bytes", dkim.SaltSize)
+	}
+	return salt, nil
+}
+
+// verifierOptions collects the circuit modes selected by the command flags.
+func verifierOptions(ctx *cli.Context) ([]dkim.Option, error) {
+	opts := make([]dkim.Option, 0)
+	if ctx.Bool(fixedExponentFlag.Name) {
+		opts = append(opts, dkim.WithFixedExponent(dkim.DefaultExponent))
+	}
+	commitment, err := dkim.ParseCommitmentHash(ctx.String(commitmentFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, dkim.WithCommitmentHash(commitment))
+	if names := ctx.StringSlice(revealHeadersFlag.Name); len(names) != 0 {
+		opts = append(opts, dkim.WithRevealedHeaders(names...))
+	}
+	if prefix := ctx.String(subjectCommandFlag.Name); prefix != "" {
+		opts = append(opts, dkim.WithSubjectCommand(prefix, ctx.Int(commandCapacityFlag.Name)))
+	}
+	if capacity := ctx.Int(senderCapacityFlag.Name); capacity != 0 {
+		opts = append(opts, dkim.WithSenderAddress(capacity))
+	}
+	if ctx.Bool(saltedSenderFlag.Name) {
+		opts = append(opts, dkim.WithSaltedSender())
+	}
+	if name := ctx.String(domainAlignmentFlag.Name); name != "" {
+		alignment, err := dkim.ParseAlignment(name)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, dkim.WithDomainAlignment(alignment))
+	}
+	if ctx.Bool(keyNameFlag.Name) {
+		opts = append(opts, dkim.WithKeyName())
+	}
+	if ctx.Bool(dateTimestampFlag.Name) {
+		opts = append(opts, dkim.WithDateTimestamp())
+	} else if ctx.Bool(timestampFlag.Name) {
+		opts = append(opts, dkim.WithTimestamp())
+	}
+	if depth := ctx.Int(keySetDepthFlag.Name); depth != 0 {
+		opts = append(opts, dkim.WithKeySet(depth))
+	}
+	return opts, nil
+}
+
+// newVerifier returns the circuit template of the mail type with the modes of the command flags,
+// the providers of the provider files are added to the built-in ones.
+func newVerifier(ctx *cli.Context, mailType string) (dkim.DKIMVerifier, error) {
+	opts, err := verifierOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registry := dkim.DefaultRegistry
+	if path := ctx.Path(providersFlag.Name); path != "" {
+		registry = registry.Clone()
+		if err := registry.Load(path); err != nil {
+			return nil, err
+		}
+	}
+	return registry.Verifier(mailType, opts...)
+}
+
+// readKeySet builds the key set tree of the circuit from the key set file.
+func readKeySet(ctx *cli.Context, circuit dkim.DKIMVerifier) (*dkim.KeySet, error) {
+	keySetFilePath := ctx.Path(keySetFileFlag.Name)
+	if keySetFilePath == "" {
+		return nil, errors.New("invalid key set file path")
+	}
+	commitment, err := dkim.ParseCommitmentHash(ctx.String(commitmentFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	txtRecords, err := keyset.ReadTxtRecords(keySetFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return keyset.Build(txtRecords, circuit.KeyBits(), commitment, ctx.Int(keySetDepthFlag.Name))
+}
+
+func exportKeySetRoot(ctx *cli.Context) error {
+	mailType := ctx.String(mailFlag.Name)
+	if mailType == "" {
+		return errors.New("invalid mail type")
+	}
+	outputPath := ctx.Path(outputFileFlag.Name)
+	if outputPath == "" {
+		return errors.New("invalid output file path")
+	}
+	circuit, err := newVerifier(ctx, mailType)
+	if err != nil {
+		return err
+	}
+	keySet, err := readKeySet(ctx, circuit)
+	if err != nil {
+		return err
+	}
+	err = keyset.ExportRoot(keySet, outputPath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.App.Writer, "Key set root:", keySet.Root().String())
+	return nil
+}
+
+func exportKeySetPath(ctx *cli.Context) error {
+	mailType := ctx.String(mailFlag.Name)
+	if mailType == "" {
+		return errors.New("invalid mail type")
+	}
+	rsaPuKeyFilePath := ctx.Path(rsaPuKeyFileFlag.Name)
+	if rsaPuKeyFilePath == "" {
+		return errors.New("invalid rsaPuKey file path")
+	}
+	outputPath := ctx.Path(outputFileFlag.Name)
+	if outputPath == "" {
+		return errors.New("invalid output file path")
+	}
+	circuit, err := newVerifier(ctx, mailType)
+	if err != nil {
+		return err
+	}
+	keySet, err := readKeySet(ctx, circuit)
+	if err != nil {
+		return err
+	}
+	rsaPuKey, err := mpc.ReadFile(rsaPuKeyFilePath)
+	if err != nil {
+		return err
+	}
+	return keyset.ExportPath(keySet, rsaPuKey, circuit.KeyBits(), outputPath)
+}
+
 func sealCircuit(ctx *cli.Context) error {
 	srsFilePath := ctx.Path(srsFileFlag.Name)
 	if srsFilePath == "" {
@@ -334,13 +745,13 @@ func sealCircuit(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("read ccs")
+	fmt.Fprintln(ctx.App.Writer, "read ccs")
 	// Generate node private key
 	pk, vk, err := mpc.GetInitParamsFromExistedMPCSetUp(ccs, srsFilePath, phase2FilePath)
 	if err != nil {
 		return err
 	}
-	fmt.Println("finish pk, vk")
+	fmt.Fprintln(ctx.App.Writer, "finish pk, vk")
 	err = mpc.ExportProvingKey(pk, provingKeyFilePath)
 	if err != nil {
 		return err
@@ -375,7 +786,7 @@ func initCircuitPhase2(ctx *cli.Context) error {
 	if ccsPath == "" {
 		return errors.New("invalid ccsFile path")
 	}
-	c, err := dkim.GetCustomDKIMVerifierWrapper(mailType)
+	c, err := newVerifier(ctx, mailType)
 	if err != nil {
 		return err
 	}
@@ -391,7 +802,7 @@ func initCircuitPhase2(ctx *cli.Context) error {
 	if _, err := p.WriteTo(sha); err != nil {
 		return err
 	}
-	fmt.Println("File challenge:", hex.EncodeToString(sha.Sum(nil)))
+	fmt.Fprintln(ctx.App.Writer, "File challenge:", hex.EncodeToString(sha.Sum(nil)))
 	return nil
 }
 
@@ -409,7 +820,7 @@ func verifyPhase2(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("Phase2 verified, and the previous challenge is", hex.EncodeToString(challenge))
+	fmt.Fprintln(ctx.App.Writer, "Phase2 verified, and the previous challenge is", hex.EncodeToString(challenge))
 	return nil
 }
 
@@ -426,12 +837,12 @@ func contributePhase2(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("Contributed to:", hex.EncodeToString(p.Challenge))
+	fmt.Fprintln(ctx.App.Writer, "Contributed to:", hex.EncodeToString(p.Challenge))
 	sha := sha256.New()
 	if _, err := p.WriteTo(sha); err != nil {
 		return err
 	}
-	fmt.Println("File challenge:", hex.EncodeToString(sha.Sum(nil)))
+	fmt.Fprintln(ctx.App.Writer, "File challenge:", hex.EncodeToString(sha.Sum(nil)))
 	return nil
 }
 
@@ -445,12 +856,12 @@ func initPhase1(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("Phase1 SRS File initials successfully, fft domain size: 2^%d\n", domain)
+	fmt.Fprintf(ctx.App.Writer, "Phase1 SRS File initials successfully, fft domain size: 2^%d\n", domain)
 	sha := sha256.New()
 	if _, err := p.WriteTo(sha); err != nil {
 		return err
 	}
-	fmt.Println("File challenge:", hex.EncodeToString(sha.Sum(nil)))
+	fmt.Fprintln(ctx.App.Writer, "File challenge:", hex.EncodeToString(sha.Sum(nil)))
 	return nil
 }
 
@@ -468,7 +879,7 @@ func verifyPhase1(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("Phase1 verified, and the previous challenge is", hex.EncodeToString(challenge))
+	fmt.Fprintln(ctx.App.Writer, "Phase1 verified, and the previous challenge is", hex.EncodeToString(challenge))
 	return nil
 }
 
@@ -486,12 +897,12 @@ func contributePhase1(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("Contributed to:", hex.EncodeToString(p.Challenge))
+	fmt.Fprintln(ctx.App.Writer, "Contributed to:", hex.EncodeToString(p.Challenge))
 	sha := sha256.New()
 	if _, err := p.WriteTo(sha); err != nil {
 		return err
 	}
-	fmt.Println("File challenge:", hex.EncodeToString(sha.Sum(nil)))
+	fmt.Fprintln(ctx.App.Writer, "File challenge:", hex.EncodeToString(sha.Sum(nil)))
 	return nil
 }
 
@@ -508,6 +919,6 @@ func sealPhase1(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("Phase1 finished, seal file to ", outputPath)
+	fmt.Fprintln(ctx.App.Writer, "Phase1 finished, seal file to ", outputPath)
 	return nil
 }
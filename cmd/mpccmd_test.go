@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/test"
+	"github.com/doubiliu/zk-email/circuit/dkim"
+	"github.com/doubiliu/zk-email/keyset"
+	"github.com/urfave/cli/v2"
+)
+
+func TestSenderCommand(t *testing.T) {
+	assert := test.NewAssert(t)
+	salt := bytes.Repeat([]byte{0x5a}, dkim.SaltSize)
+	for _, c := range []struct {
+		args     []string
+		expected func() ([]*big.Int, error)
+	}{
+		{
+			args: []string{"--address", "Alice@Example.com", "--senderCapacity", "64", "--senderSalt", hex.EncodeToString(salt)},
+			expected: func() ([]*big.Int, error) {
+				return dkim.SaltedSenderCommitment(dkim.CommitmentSHA256, "Alice@Example.com", 64, salt)
+			},
+		},
+		{
+			args: []string{"--senderDomain", "example.com", "--senderSalt", hex.EncodeToString(salt)},
+			expected: func() ([]*big.Int, error) {
+				return dkim.SaltedSenderDomainCommitment(dkim.CommitmentSHA256, "example.com", dkim.DomainCapacity, salt)
+			},
+		},
+		{
+			args: []string{"--address", "Alice@Example.com", "--senderCapacity", "64"},
+			expected: func() ([]*big.Int, error) {
+				return dkim.SenderCommitment(dkim.CommitmentSHA256, "Alice@Example.com", 64)
+			},
+		},
+	} {
+		expected, err := c.expected()
+		assert.NoError(err)
+		output := new(bytes.Buffer)
+		app := newApp()
+		app.Writer = output
+		assert.NoError(app.Run(append([]string{"mpccmd", "sender"}, c.args...)))
+		assert.Equal(fmt.Sprintln("Sender:", expected), output.String())
+	}
+	// The salt must be 32 bytes.
+	app := newApp()
+	app.Writer = new(bytes.Buffer)
+	assert.Error(app.Run([]string{"mpccmd", "sender", "--senderDomain", "example.com", "--senderSalt", "00"}))
+}
+
+func TestSenderSalt(t *testing.T) {
+	assert := test.NewAssert(t)
+	// The generated salt is printed, the prover needs it to open the sender commitment.
+	var salt []byte
+	output := new(bytes.Buffer)
+	app := &cli.App{
+		Writer: output,
+		Flags:  []cli.Flag{senderSaltFlag},
+		Action: func(ctx *cli.Context) error {
+			var err error
+			salt, err = senderSalt(ctx, true)
+			return err
+		},
+	}
+	assert.NoError(app.Run([]string{"mpccmd"}))
+	assert.Equal(dkim.SaltSize, len(salt))
+	assert.Equal(fmt.Sprintln("Sender salt:", hex.EncodeToString(salt)), output.String())
+}
+
+func TestKeySetRoot(t *testing.T) {
+	assert := test.NewAssert(t)
+	txtRecords, err := keyset.ReadTxtRecords("../circuit/dkim/testRsaPubKey.txt")
+	assert.NoError(err)
+	dir := t.TempDir()
+	keySetPath, rootPath := filepath.Join(dir, "keyset.txt"), filepath.Join(dir, "root.txt")
+	assert.NoError(os.WriteFile(keySetPath, []byte(strings.Join(txtRecords, "\n")), 0o644))
+	output := new(bytes.Buffer)
+	app := newApp()
+	app.Writer = output
+	assert.NoError(app.Run([]string{"mpccmd", "keyset", "root", "--mailType", "foxmail", "--commitment", "poseidon2", "--keySetDepth", "2", "--keySet", keySetPath, "--output", rootPath}))
+	keySet, err := keyset.Build(txtRecords, 1024, dkim.CommitmentPoseidon2, 2)
+	assert.NoError(err)
+	assert.Equal(fmt.Sprintln("Key set root:", keySet.Root().String()), output.String())
+	root, err := os.ReadFile(rootPath)
+	assert.NoError(err)
+	assert.Equal(keySet.Root().String()+"\n", string(root))
+}
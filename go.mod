@@ -7,6 +7,7 @@ require (
 	github.com/consensys/gnark-crypto v0.18.0
 	github.com/containerd/containerd v1.7.18
 	github.com/urfave/cli/v2 v2.27.7
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -32,7 +33,6 @@ require (
 	golang.org/x/exp v0.0.0-20250811191247-51f88131bc50 // indirect
 	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/consensys/gnark => github.com/bane-labs/gnark v0.13.1-0.20250721034632-d1e20e51b116
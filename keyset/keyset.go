@@ -0,0 +1,86 @@
+package keyset
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/doubiliu/zk-email/circuit/dkim"
+)
+
+/**
+ * Function: ReadTxtRecords
+ * @Description: read the DKIM DNS TXT records of the key set, one record per line
+ * @param path: key set file path
+ * @return []string: TXT records
+ */
+func ReadTxtRecords(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	txtRecords := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			txtRecords = append(txtRecords, line)
+		}
+	}
+	return txtRecords, nil
+}
+
+/**
+ * Function: Build
+ * @Description: build the key set tree from the DKIM DNS TXT records
+ * @param txtRecords: TXT records of the allowlisted keys
+ * @param keyBits: RSA key size of the circuit
+ * @param h: commitment hash of the circuit
+ * @param depth: key set depth of the circuit
+ * @return *dkim.KeySet: key set tree
+ */
+func Build(txtRecords []string, keyBits int, h dkim.CommitmentHash, depth int) (*dkim.KeySet, error) {
+	keyHashes := make([]*big.Int, len(txtRecords))
+	for i, txtRecord := range txtRecords {
+		keyHash, err := dkim.PublicKeyHash(txtRecord, keyBits, h)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		keyHashes[i] = keyHash[0]
+	}
+	return dkim.NewKeySet(h, depth, keyHashes)
+}
+
+/**
+ * Function: ExportRoot
+ * @Description: export the key set root, it is the KeyRoot public input of the verifier contract
+ * @param keySet: key set tree
+ * @param path: root file path
+ */
+func ExportRoot(keySet *dkim.KeySet, path string) error {
+	return os.WriteFile(path, []byte(keySet.Root().String()+"\n"), 0o644)
+}
+
+/**
+ * Function: ExportPath
+ * @Description: export the inclusion proof of a key, the first line is the leaf index followed by the siblings from the leaf up
+ * @param keySet: key set tree
+ * @param txtRecord: TXT record of the key
+ * @param keyBits: RSA key size of the circuit
+ * @param path: proof file path
+ */
+func ExportPath(keySet *dkim.KeySet, txtRecord string, keyBits int, path string) error {
+	keyHash, err := dkim.PublicKeyHash(txtRecord, keyBits, keySet.Hash())
+	if err != nil {
+		return err
+	}
+	index, siblings, err := keySet.Path(keyHash[0])
+	if err != nil {
+		return err
+	}
+	lines := []string{fmt.Sprint(index)}
+	for _, sibling := range siblings {
+		lines = append(lines, sibling.String())
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
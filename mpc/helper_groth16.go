@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
 	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
@@ -263,7 +264,7 @@ func ProveCircuit(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk gro
 	if err != nil {
 		return err
 	}
-	proofData, cmts, cmtPok, err := GetContractInput(proof)
+	proofData, cmts, cmtPok, input, nullifier, err := GetContractInput(proof, publicWitness)
 	if err != nil {
 		return err
 	}
@@ -284,6 +285,16 @@ func ProveCircuit(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk gro
 	for i := 0; i < len(cmtPok); i++ {
 		fmt.Println(cmtPok[i].String())
 	}
+	fmt.Println()
+	// public inputs
+	fmt.Println("Input:")
+	for i := 0; i < len(input); i++ {
+		fmt.Println(input[i].String())
+	}
+	fmt.Println()
+	// nullifier
+	fmt.Println("Nullifier:")
+	fmt.Println(nullifier.String())
 	return nil
 }
 
@@ -291,13 +302,23 @@ func ProveCircuit(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk gro
  * Function: GetContractInput
  * @Description: get the data submitted to the chain
  * @param proof: zk proof
- * @return []*big.Int: data submitted to the chain
+ * @param publicWitness: public witness of the proof
+ * @return []*big.Int: data submitted to the chain, followed by the public input array of the verifier contract
+ * @return *big.Int: nullifier of the email, it is the last public input
  */
-func GetContractInput(proof groth16.Proof) ([8]*big.Int, []*big.Int, [2]*big.Int, error) {
+func GetContractInput(proof groth16.Proof, publicWitness witness.Witness) ([8]*big.Int, []*big.Int, [2]*big.Int, []*big.Int, *big.Int, error) {
 	// Solidity contract inputs
 	proofInBn254, ok := proof.(*groth16_bn254.Proof)
 	if !ok {
-		return [8]*big.Int{}, []*big.Int{}, [2]*big.Int{}, fmt.Errorf("invalid proof type")
+		return [8]*big.Int{}, []*big.Int{}, [2]*big.Int{}, []*big.Int{}, nil, fmt.Errorf("invalid proof type")
+	}
+	publicVector, ok := publicWitness.Vector().(fr.Vector)
+	if !ok || len(publicVector) == 0 {
+		return [8]*big.Int{}, []*big.Int{}, [2]*big.Int{}, []*big.Int{}, nil, fmt.Errorf("invalid witness type")
+	}
+	input := make([]*big.Int, len(publicVector))
+	for i := range publicVector {
+		input[i] = publicVector[i].BigInt(new(big.Int))
 	}
 	proofBytes := proofInBn254.MarshalSolidity()
 	fpSize := 4 * 8
@@ -317,7 +338,7 @@ func GetContractInput(proof groth16.Proof) ([8]*big.Int, []*big.Int, [2]*big.Int
 	// commitmentPok
 	cmtPok[0] = new(big.Int).SetBytes(proofBytes[fpSize*8+4+2*cmtCount*fpSize : fpSize*8+4+2*cmtCount*fpSize+fpSize])
 	cmtPok[1] = new(big.Int).SetBytes(proofBytes[fpSize*8+4+2*cmtCount*fpSize+fpSize : fpSize*8+4+2*cmtCount*fpSize+2*fpSize])
-	return prf, cmts, cmtPok, nil
+	return prf, cmts, cmtPok, input, input[len(input)-1], nil
 }
 
 /**
@@ -337,5 +358,66 @@ func ExportContract(vk groth16.VerifyingKey, path string) error {
 	if err != nil {
 		return err
 	}
+	_, err = contract.WriteString(boundVerifierContract(vk))
+	if err != nil {
+		return err
+	}
 	return nil
 }
+
+/**
+ * Function: boundVerifierContract
+ * @Description: solidity contract checking the binding public input before the proof, the binding is the public input
+ *               before the nullifier and carries block.chainid << 160 | msg.sender. It also checks the freshness of
+ *               the timestamp public input, which comes before the binding in the timestamp mode, read from the
+ *               public inputs of the proof so that callers can not pass another time, and records the nullifier,
+ *               the last public input, so that an email is accepted once
+ * @param vk: verifying key
+ * @return string: contract extending the exported Verifier
+ */
+func boundVerifierContract(vk groth16.VerifyingKey) string {
+	nbPublic := vk.NbPublicWitness()
+	params, args := "", ""
+	if vkBn254, ok := vk.(*groth16_bn254.VerifyingKey); ok && len(vkBn254.PublicAndCommitmentCommitted) > 0 {
+		params = fmt.Sprintf("        uint256[%d] calldata commitments,\n        uint256[2] calldata commitmentPok,\n", 2*len(vkBn254.PublicAndCommitmentCommitted))
+		args = "commitments, commitmentPok, "
+	}
+	return fmt.Sprintf(`
+
+/// @title BoundVerifier
+/// @notice Verifies proofs bound to the caller and the chain. Inherit it in the application contract,
+/// so that msg.sender is the account submitting the proof.
+contract BoundVerifier is Verifier {
+    /// @notice Signing times this many seconds ahead of the block are accepted, clocks of mail servers drift.
+    uint256 public constant MAX_CLOCK_SKEW = 300;
+
+    /// @notice Nullifiers of the proofs already used, see useNullifier.
+    mapping(uint256 => bool) public usedNullifiers;
+
+    function verifyBoundProof(
+        uint256[8] calldata proof,
+%s        uint256[%d] calldata input
+    ) public view {
+        require(input[%d] == (block.chainid << 160) | uint256(uint160(msg.sender)), "proof is bound to another caller");
+        verifyProof(proof, %sinput);
+    }
+
+    /// @notice Reverts unless the DKIM signing time, the Timestamp public input of the timestamp mode,
+    /// is at most maxAge seconds before the block. Only call it for circuits of the timestamp mode,
+    /// with the public inputs of a proof checked by verifyBoundProof.
+    function requireFresh(uint256[%d] calldata input, uint256 maxAge) internal view {
+        uint256 timestamp = input[%d];
+        require(timestamp <= block.timestamp + MAX_CLOCK_SKEW, "email is signed in the future");
+        require(block.timestamp <= timestamp + maxAge, "email is too old");
+    }
+
+    /// @notice Reverts if the nullifier, the last public input, was used before and marks it as used,
+    /// so that an email is accepted once. Call it with the public inputs of a proof checked by verifyBoundProof.
+    function useNullifier(uint256[%d] calldata input) internal {
+        uint256 nullifier = input[%d];
+        require(!usedNullifiers[nullifier], "nullifier is already used");
+        usedNullifiers[nullifier] = true;
+    }
+}
+`, params, nbPublic, nbPublic-2, args, nbPublic, nbPublic-3, nbPublic, nbPublic-1)
+}
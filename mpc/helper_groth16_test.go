@@ -0,0 +1,43 @@
+package mpc
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+// timestampCircuit has the public inputs of the timestamp mode of the DKIM verifier.
+type timestampCircuit struct {
+	PubInputHash []frontend.Variable `gnark:",public"`
+	Timestamp    frontend.Variable   `gnark:",public"`
+	Binding      frontend.Variable   `gnark:",public"`
+	Nullifier    frontend.Variable   `gnark:",public"`
+}
+
+func (c *timestampCircuit) Define(api frontend.API) error {
+	api.AssertIsDifferent(api.Add(c.PubInputHash[0], c.PubInputHash[1], c.Timestamp, c.Binding), c.Nullifier)
+	return nil
+}
+
+func TestBoundVerifierContract(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &timestampCircuit{PubInputHash: make([]frontend.Variable, 2)})
+	assert.NoError(err)
+	_, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.Equal(5, vk.NbPublicWitness())
+	contract := boundVerifierContract(vk)
+	// The binding and the timestamp are read from the public inputs of the proof.
+	assert.Contains(contract, "uint256[5] calldata input\n    ) public view {\n        require(input[3] == (block.chainid << 160) | uint256(uint160(msg.sender))")
+	assert.Contains(contract, "function requireFresh(uint256[5] calldata input, uint256 maxAge) internal view {\n        uint256 timestamp = input[2];")
+	assert.NotContains(contract, "requireFresh(uint256 timestamp")
+	// The nullifier is the last public input.
+	assert.Contains(contract, "function useNullifier(uint256[5] calldata input) internal {\n        uint256 nullifier = input[4];")
+	assert.Contains(contract, "mapping(uint256 => bool) public usedNullifiers;")
+	// Every format verb has its argument.
+	assert.NotContains(contract, "%!")
+}